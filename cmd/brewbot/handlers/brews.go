@@ -3,13 +3,14 @@ package handlers
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/platform/discord"
+	"github.com/benjaminbartels/brewbot/internal/styles"
 	"github.com/bwmarrin/discordgo"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -20,14 +21,34 @@ const (
 	logSubCommand         = "log"
 	listSubCommand        = "list"
 	deleteSubCommand      = "delete"
+	detailsSubCommand     = "details"
+	brewDetailsModal      = "brewdetails"
+	brewDetailsComponent  = "brewdetails"
+	detailsInput          = "details"
+	maxModalTitle         = 45
+	maxDetailsLength      = 1000
 	leaderboardSubCommand = "leaderboard"
+	dateFormat            = "2006-01-02"
+	maxMessageLength      = 2000
 )
 
 type BrewsHandler struct {
 	BrewRepo          dynamo.BrewRepo
 	LeaderboardRepo   dynamo.LeaderboardRepo
+	DigestRepo        dynamo.DigestRepo
+	SnapshotRepo      dynamo.SnapshotRepo
+	TeamRepo          dynamo.TeamRepo
 	LeaderboardCutoff time.Time
+	Bot               *discord.Bot
 	Logger            *logrus.Logger
+
+	NotificationPreferenceRepo dynamo.NotificationPreferenceRepo
+	NotificationCooldown       time.Duration
+
+	RoleManager *discord.RoleManager
+	RoleAwards  RoleAwards
+
+	*GuideResolver
 }
 
 func BrewCommand() *discordgo.ApplicationCommand {
@@ -52,6 +73,30 @@ func BrewCommand() *discordgo.ApplicationCommand {
 						Description: "How many gallons?",
 						Required:    true,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "details",
+						Description: "Entry details a specialty style requires, e.g. the base style or special ingredient",
+					},
+				},
+			},
+			{
+				Name:        detailsSubCommand,
+				Description: "Set the entry details of a homebrew",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "id",
+						Description: "ID of homebrew",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "details",
+						Description: "Entry details, e.g. the base style or special ingredient",
+						Required:    true,
+					},
 				},
 			},
 			{
@@ -76,7 +121,26 @@ func BrewCommand() *discordgo.ApplicationCommand {
 				Name:        leaderboardSubCommand,
 				Description: "Show the leaderboard",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "at",
+						Description: "Show the standings as of a date (YYYY-MM-DD)",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "scope",
+						Description: "Rank individual members or teams",
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: membersScope, Value: membersScope},
+							{Name: teamsScope, Value: teamsScope},
+						},
+					},
+				},
 			},
+			digestSubCommandGroupOption(),
+			notifySubCommandOption(),
+			teamSubCommandGroupOption(),
 		},
 	}
 }
@@ -96,8 +160,16 @@ func (h *BrewsHandler) BrewHandler(s *discordgo.Session, i *discordgo.Interactio
 		err = h.handleList(ctx, s, i, user)
 	case deleteSubCommand:
 		err = h.handleDelete(ctx, s, i, user, opts)
+	case detailsSubCommand:
+		err = h.handleDetails(ctx, s, i, user, opts)
 	case leaderboardSubCommand:
-		err = h.handleLeaderboard(ctx, s, i)
+		err = h.handleLeaderboard(ctx, s, i, opts)
+	case digestSubCommandGroup:
+		err = h.handleDigest(ctx, s, i, opts)
+	case notifySubCommand:
+		err = h.handleNotify(ctx, s, i, user, opts)
+	case teamSubCommandGroup:
+		err = h.handleTeam(ctx, s, i, user, opts)
 	}
 
 	if err != nil {
@@ -137,6 +209,10 @@ func (h *BrewsHandler) handleLog(ctx context.Context, s *discordgo.Session, i *d
 		Amount:   floatAmount,
 	}
 
+	if opt, ok := optionsByName(opts)["details"]; ok {
+		brew.Details = strings.TrimSpace(opt.StringValue())
+	}
+
 	if err := h.BrewRepo.Save(ctx, brew); err != nil {
 		return errors.Wrap(err, "could not save brew")
 	}
@@ -145,14 +221,249 @@ func (h *BrewsHandler) handleLog(ctx context.Context, s *discordgo.Session, i *d
 		return errors.Wrapf(err, "could not refresh leaderboard for user %s", user.ID)
 	}
 
-	message := fmt.Sprintf("%s brewed %0.2f gallons of %s!", name, floatAmount, style)
-	if err := respondToChannel(s, i, message, false); err != nil {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: brewMessage(brew),
+		},
+	}
+
+	// A brew of a style with entry instructions is announced straight away with a button that opens a modal asking
+	// for its details, so the brew is acknowledged even if the brewer never fills them in.
+	if brew.Details == "" {
+		entryStyle, err := h.entryStyle(ctx, i.GuildID, style)
+		if err != nil {
+			h.Logger.WithError(err).Warnf("could not look up entry instructions for brew %s", brew.ID)
+		}
+
+		if entryStyle != nil {
+			response.Data.Content = truncate(fmt.Sprintf("%s\n%s needs entry details, add them with the button "+
+				"below or `/brew details id:%s`", brewMessage(brew), entryStyle.Name, brew.ID), maxMessageLength)
+			response.Data.Components = []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.Button{
+							Label:    "Add entry details",
+							Style:    discordgo.PrimaryButton,
+							CustomID: fmt.Sprintf("%s:%s", brewDetailsComponent, brew.ID),
+						},
+					},
+				},
+			}
+		}
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
 		return errors.Wrap(err, "could not respond with log success message")
 	}
 
 	return nil
 }
 
+func brewMessage(brew *dynamo.Brew) string {
+	message := fmt.Sprintf("%s brewed %0.2f gallons of %s!", brew.Username, brew.Amount, brew.Style)
+	if brew.Details != "" {
+		message += fmt.Sprintf("\n> %s", brew.Details)
+	}
+
+	return truncate(message, maxMessageLength)
+}
+
+// entryStyle finds the style of a brew when it has entry instructions. The guild's guide is searched first and then
+// the other guides, so that a mead or cider is found even when the guild's guide is a beer guide.
+func (h *BrewsHandler) entryStyle(ctx context.Context, guildID, text string) (*styles.Style, error) {
+	guildGuide, err := h.guideName(ctx, guildID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not resolve style guide")
+	}
+
+	for _, name := range append([]string{guildGuide}, h.GuideRepo.Names()...) {
+		repo := h.GuideRepo.Guide(name)
+		if repo == nil {
+			continue
+		}
+
+		if style := matchStyle(ctx, repo, text); style != nil {
+			if style.EntryInstructions == "" {
+				return nil, nil
+			}
+
+			return style, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// promptForDetails responds with a modal showing the entry instructions of the style of the brew, which ask for
+// details such as the base style or special ingredient for the brew to be entered into a competition.
+func promptForDetails(s *discordgo.Session, i *discordgo.InteractionCreate, brew *dynamo.Brew,
+	style *styles.Style,
+) error {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: fmt.Sprintf("%s:%s", brewDetailsModal, brew.ID),
+			Title:    truncate(fmt.Sprintf("%s %s", style.Number, style.Name), maxModalTitle),
+			Components: []discordgo.MessageComponent{
+				discordgo.TextDisplay{
+					Content: truncate(style.EntryInstructions, maxMessageLength),
+				},
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    detailsInput,
+							Label:       "Entry details",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "e.g. the base style or special ingredient",
+							Required:    true,
+							MaxLength:   maxDetailsLength,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not respond with details modal")
+	}
+
+	return nil
+}
+
+// BrewDetailsComponentHandler opens the modal asking for the entry details of a brew when its brewer presses the
+// button on the brew's announcement.
+func (h *BrewsHandler) BrewDetailsComponentHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx := context.Background()
+
+	id := strings.TrimPrefix(i.MessageComponentData().CustomID, brewDetailsComponent+":")
+
+	brew, err := h.BrewRepo.Get(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "could not get brew %s", id)
+	}
+
+	if brew == nil || brew.UserID != i.Member.User.ID {
+		if err := respondToChannel(s, i, "Only the brewer can add the details of this brew", true); err != nil {
+			return errors.Wrap(err, "could not respond with wrong user error")
+		}
+
+		return nil
+	}
+
+	style, err := h.entryStyle(ctx, i.GuildID, brew.Style)
+	if err != nil {
+		return errors.Wrapf(err, "could not look up entry instructions for brew %s", id)
+	}
+
+	if style == nil {
+		if err := respondToChannel(s, i, fmt.Sprintf("Add the details with `/brew details id:%s`", id),
+			true); err != nil {
+			return errors.Wrap(err, "could not respond with details hint")
+		}
+
+		return nil
+	}
+
+	if err := promptForDetails(s, i, brew, style); err != nil {
+		return errors.Wrapf(err, "could not prompt for details of brew %s", id)
+	}
+
+	return nil
+}
+
+// BrewDetailsModalHandler saves the entry details submitted for a brew and replaces the brew's announcement with one
+// that includes them.
+func (h *BrewsHandler) BrewDetailsModalHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx := context.Background()
+
+	id := strings.TrimPrefix(i.ModalSubmitData().CustomID, brewDetailsModal+":")
+
+	brew, err := h.BrewRepo.Get(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "could not get brew %s", id)
+	}
+
+	if brew == nil || brew.UserID != i.Member.User.ID {
+		if err := respondToChannel(s, i, fmt.Sprintf("Brew %s not found", id), true); err != nil {
+			return errors.Wrap(err, "could not respond with not found error")
+		}
+
+		return nil
+	}
+
+	brew.Details = strings.TrimSpace(modalValue(i.ModalSubmitData(), detailsInput))
+
+	if err := h.BrewRepo.Save(ctx, brew); err != nil {
+		return errors.Wrapf(err, "could not save brew %s", id)
+	}
+
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    brewMessage(brew),
+			Components: []discordgo.MessageComponent{},
+		},
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not respond with brew details")
+	}
+
+	return nil
+}
+
+// modalValue returns the value of the text input with the given custom ID in a submitted modal.
+func modalValue(data discordgo.ModalSubmitInteractionData, customID string) string {
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+
+		for _, child := range row.Components {
+			if input, ok := child.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value
+			}
+		}
+	}
+
+	return ""
+}
+
+func (h *BrewsHandler) handleDetails(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	user *discordgo.User, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	options := optionsByName(opts)
+	id := options["id"].StringValue()
+
+	brew, err := h.BrewRepo.Get(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "could not get brew %s", id)
+	}
+
+	if brew == nil || brew.UserID != user.ID {
+		if err := respondToChannel(s, i, fmt.Sprintf("Brew %s not found", id), true); err != nil {
+			return errors.Wrap(err, "could not respond with not found error")
+		}
+
+		return nil
+	}
+
+	brew.Details = strings.TrimSpace(options["details"].StringValue())
+
+	if err := h.BrewRepo.Save(ctx, brew); err != nil {
+		return errors.Wrapf(err, "could not save brew %s", id)
+	}
+
+	if err := respondToChannel(s, i, fmt.Sprintf("Updated the details of your %s brew", brew.Style), true); err != nil {
+		return errors.Wrap(err, "could not respond with details success message")
+	}
+
+	return nil
+}
+
 func (h *BrewsHandler) handleList(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
 	user *discordgo.User,
 ) error {
@@ -228,14 +539,79 @@ func (h *BrewsHandler) handleDelete(ctx context.Context, s *discordgo.Session, i
 }
 
 func (h *BrewsHandler) handleLeaderboard(ctx context.Context, s *discordgo.Session,
-	i *discordgo.InteractionCreate,
+	i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption,
 ) error {
-	leaderboardEntries, err := h.LeaderboardRepo.GetAll(ctx)
+	var (
+		standings []dynamo.Standing
+		asOf      = time.Now().UTC()
+		title     = "Leaderboard:\n"
+	)
+
+	options := optionsByName(opts)
+
+	if opt, ok := options["at"]; ok {
+		date, err := time.Parse(dateFormat, opt.StringValue())
+		if err != nil {
+			if err := respondToChannel(s, i, fmt.Sprintf("Invalid date: %s", opt.Value), true); err != nil {
+				return errors.Wrap(err, "could not respond with invalid date error")
+			}
+
+			return nil
+		}
+
+		snapshot, err := h.SnapshotRepo.GetAsOf(ctx, h.season(), date.Format(dateFormat))
+		if err != nil {
+			return errors.Wrapf(err, "could not get leaderboard snapshot as of %s", date.Format(dateFormat))
+		}
+
+		if snapshot == nil {
+			message := fmt.Sprintf("No leaderboard history as of %s", date.Format(dateFormat))
+			if err := respondToChannel(s, i, message, true); err != nil {
+				return errors.Wrap(err, "could not respond with no history error")
+			}
+
+			return nil
+		}
+
+		if asOf, err = time.Parse(dateFormat, snapshot.Date); err != nil {
+			return errors.Wrapf(err, "could not parse snapshot date %s", snapshot.Date)
+		}
+
+		standings = snapshot.Standings
+		title = fmt.Sprintf("Leaderboard as of %s:\n", snapshot.Date)
+	} else {
+		leaderboardEntries, err := h.LeaderboardRepo.GetAll(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get brews")
+		}
+
+		standings = rankStandings(leaderboardEntries)
+	}
+
+	previousDate := asOf.AddDate(0, 0, -1).Format(dateFormat)
+
+	previous, err := h.SnapshotRepo.GetAsOf(ctx, h.season(), previousDate)
 	if err != nil {
-		return errors.Wrap(err, "could not get brews")
+		return errors.Wrapf(err, "could not get leaderboard snapshot as of %s", previousDate)
+	}
+
+	var previousStandings []dynamo.Standing
+	if previous != nil {
+		previousStandings = previous.Standings
 	}
 
-	if len(leaderboardEntries) == 0 {
+	if opt, ok := options["scope"]; ok && opt.StringValue() == teamsScope {
+		teams, err := h.TeamRepo.GetAll(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get teams")
+		}
+
+		standings = teamStandings(teams, standings)
+		previousStandings = teamStandings(teams, previousStandings)
+		title = "Team " + title
+	}
+
+	if len(standings) == 0 {
 		if err := respondToChannel(s, i, "No Brews yet!", true); err != nil {
 			return errors.Wrap(err, "could not respond with no brews error")
 		}
@@ -243,38 +619,27 @@ func (h *BrewsHandler) handleLeaderboard(ctx context.Context, s *discordgo.Sessi
 		return nil
 	}
 
-	sort.Slice(leaderboardEntries, func(i, j int) bool {
-		return leaderboardEntries[i].Volume > leaderboardEntries[j].Volume
-	})
-
 	var builder strings.Builder
 
-	writer := tabwriter.NewWriter(&builder, 0, 5, 2, ' ', 0)
-
-	fmt.Fprintln(writer, "\tName\tCount\tGallons")
+	if err := writeStandings(&builder, standings, standingsByUserID(previousStandings)); err != nil {
+		return errors.Wrap(err, "could not write standings")
+	}
 
 	var (
 		totalCount  int
 		totalVolume float64
 	)
 
-	for i, entry := range leaderboardEntries {
-		fmt.Fprintf(writer, "%d\t%s\t%d\t%6.02f\t\n", i+1, entry.Username, entry.Count, entry.Volume)
-		totalCount += entry.Count
-		totalVolume += entry.Volume
+	for _, standing := range standings {
+		totalCount += standing.Count
+		totalVolume += standing.Volume
 	}
 
-	fmt.Fprintf(writer, "---------------------------------------\n")
-
-	fmt.Fprintf(writer, "Total Batches: %d Total Volume: %6.02f\n", totalCount, totalVolume)
+	fmt.Fprintf(&builder, "---------------------------------------\n")
 
-	if err := writer.Flush(); err != nil {
-		return errors.Wrap(err, "could not flush to channel")
-	}
+	fmt.Fprintf(&builder, "Total Batches: %d Total Volume: %6.02f\n", totalCount, totalVolume)
 
-	fmt.Fprintf(writer, "Total Batches: %d\t Total Volume: %6.02f\t\n", totalCount, totalVolume)
-
-	message := "Leaderboard:\n"
+	message := title
 
 	message += "```\n" + builder.String() + "```"
 
@@ -286,6 +651,49 @@ func (h *BrewsHandler) handleLeaderboard(ctx context.Context, s *discordgo.Sessi
 }
 
 func (h *BrewsHandler) refreshLeaderboard(ctx context.Context, userID string) error {
+	leaderboardEntries, err := h.LeaderboardRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get leaderboard entries")
+	}
+
+	before := rankStandings(leaderboardEntries)
+
+	if err := h.refreshLeaderboardEntry(ctx, userID); err != nil {
+		return errors.Wrapf(err, "could not refresh leaderboard entry for user %s", userID)
+	}
+
+	leaderboardEntries, err = h.LeaderboardRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get leaderboard entries")
+	}
+
+	after := rankStandings(leaderboardEntries)
+
+	snapshot := &dynamo.LeaderboardSnapshot{
+		Season:    h.season(),
+		Date:      time.Now().UTC().Format(dateFormat),
+		Standings: after,
+	}
+
+	if err := h.SnapshotRepo.Save(ctx, snapshot); err != nil {
+		return errors.Wrapf(err, "could not save leaderboard snapshot for %s", snapshot.Date)
+	}
+
+	h.notifyOvertaken(ctx, userID, before, after)
+
+	if err := h.reconcileRoles(after); err != nil {
+		h.Logger.WithError(err).Warn("could not reconcile leaderboard roles")
+	}
+
+	return nil
+}
+
+// season identifies the current leaderboard season by its cutoff date.
+func (h *BrewsHandler) season() string {
+	return h.LeaderboardCutoff.Format(dateFormat)
+}
+
+func (h *BrewsHandler) refreshLeaderboardEntry(ctx context.Context, userID string) error {
 	brews, err := h.BrewRepo.GetByUserID(ctx, userID, h.LeaderboardCutoff.String())
 	if err != nil {
 		return errors.Wrapf(err, "could not get brew for user %s", userID)
@@ -346,3 +754,126 @@ func respondToChannel(s *discordgo.Session, i *discordgo.InteractionCreate, mess
 
 	return nil
 }
+
+// deferResponse acknowledges the interaction without a message, for commands that may take longer than Discord allows
+// for a response. The response is then sent with editResponse.
+func deferResponse(s *discordgo.Session, i *discordgo.InteractionCreate, isEphemeral bool) error {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{},
+	}
+
+	if isEphemeral {
+		//nolint: gomnd
+		response.Data.Flags = 1 << 6
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not send deferred interaction response")
+	}
+
+	return nil
+}
+
+// editResponse replaces the response to the interaction, such as one sent by deferResponse, with message.
+func editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, message string) error {
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &message}); err != nil {
+		return errors.Wrap(err, "could not edit interaction response")
+	}
+
+	return nil
+}
+
+// respondWithChunks responds with text that may be longer than a single message allows, sending whatever does not
+// fit in the response as follow-up messages.
+func respondWithChunks(s *discordgo.Session, i *discordgo.InteractionCreate, text string, isEphemeral bool) error {
+	chunks := splitText(text, maxMessageLength)
+
+	if err := respondToChannel(s, i, chunks[0], isEphemeral); err != nil {
+		return errors.Wrap(err, "could not send interaction response")
+	}
+
+	for _, chunk := range chunks[1:] {
+		params := &discordgo.WebhookParams{
+			Content: chunk,
+		}
+
+		if isEphemeral {
+			//nolint: gomnd
+			params.Flags = 1 << 6
+		}
+
+		if _, err := s.FollowupMessageCreate(i.Interaction, true, params); err != nil {
+			return errors.Wrap(err, "could not send follow-up message")
+		}
+	}
+
+	return nil
+}
+
+func respondWithEmbed(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed,
+	isEphemeral bool,
+) error {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	}
+
+	if isEphemeral {
+		//nolint: gomnd
+		response.Data.Flags = 1 << 6
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not send interaction response")
+	}
+
+	return nil
+}
+
+func respondWithChoices(s *discordgo.Session, i *discordgo.InteractionCreate,
+	choices []*discordgo.ApplicationCommandOptionChoice,
+) error {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not send autocomplete response")
+	}
+
+	return nil
+}
+
+func focusedOption(
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, opt := range opts {
+		if opt.Focused {
+			return opt
+		}
+	}
+
+	return nil
+}
+
+func optionsByName(
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opts))
+
+	for _, opt := range opts {
+		m[opt.Name] = opt
+	}
+
+	return m
+}
+
+func isAdmin(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions&discordgo.PermissionManageServer != 0
+}
@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/styles"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const (
+	challengeSubCommandGroup = "challenge"
+	startSubCommand          = "start"
+	endSubCommand            = "end"
+	currentSubCommand        = "current"
+	historySubCommand        = "history"
+	autoSubCommand           = "auto"
+	maxChallengeHistory      = 12
+	maxAnnouncementLength    = 500
+	challengeDateFormat      = "January 2, 2006"
+)
+
+func challengeSubCommandGroupOption() *discordgo.ApplicationCommandOption {
+	channelOption := &discordgo.ApplicationCommandOption{
+		Type:         discordgo.ApplicationCommandOptionChannel,
+		Name:         "channel",
+		Description:  "Channel to announce the challenge and its results in",
+		ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+		Required:     true,
+	}
+
+	return &discordgo.ApplicationCommandOption{
+		Name:        challengeSubCommandGroup,
+		Description: "Style of the month challenges",
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        startSubCommand,
+				Description: "Start a style challenge",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					channelOption,
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "number",
+						Description:  "Style number to brew (default a random style)",
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "days",
+						Description: "How many days the challenge runs for (default until the end of the month)",
+					},
+				},
+			},
+			{
+				Name:        endSubCommand,
+				Description: "End the running challenge now and post the results",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        currentSubCommand,
+				Description: "Show the running challenge",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        historySubCommand,
+				Description: "Show past challenges",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        autoSubCommand,
+				Description: "Automatically start a random challenge each month",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "enabled",
+						Description: "Whether to start challenges automatically",
+						Required:    true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Channel to announce the challenges and their results in",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (h *StylesHandler) handleChallenge(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	subcommand := opts[0].Name
+
+	if subcommand != currentSubCommand && subcommand != historySubCommand && !isAdmin(i) {
+		if err := respondToChannel(s, i, "Only server managers can manage challenges", true); err != nil {
+			return errors.Wrap(err, "could not respond with permission error")
+		}
+
+		return nil
+	}
+
+	challenges, err := h.ChallengeRepo.GetByGuildID(ctx, i.GuildID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get challenges for guild %s", i.GuildID)
+	}
+
+	var message string
+
+	switch subcommand {
+	case startSubCommand:
+		message, err = h.handleChallengeStart(ctx, i, challenges, opts[0].Options)
+	case endSubCommand:
+		message, err = h.handleChallengeEnd(ctx, challenges)
+	case currentSubCommand:
+		message, err = h.handleChallengeCurrent(ctx, challenges)
+	case historySubCommand:
+		message = challengeHistory(challenges)
+	case autoSubCommand:
+		message, err = h.handleChallengeAuto(ctx, i, opts[0].Options)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "could not %s challenge", subcommand)
+	}
+
+	if err := respondToChannel(s, i, message, true); err != nil {
+		return errors.Wrap(err, "could not respond with challenge message")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleChallengeStart(ctx context.Context, i *discordgo.InteractionCreate,
+	challenges []dynamo.Challenge, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) (string, error) {
+	if current := openChallenge(challenges); current != nil {
+		return fmt.Sprintf("The %s %s challenge is already running until %s", current.StyleNumber,
+			current.StyleName, formatChallengeDate(current.EndsAt)), nil
+	}
+
+	options := optionsByName(opts)
+	now := time.Now().UTC()
+	endsAt := endOfMonth(now)
+
+	if opt, ok := options["days"]; ok {
+		if opt.IntValue() < 1 {
+			return "A challenge must run for at least a day", nil
+		}
+
+		endsAt = now.AddDate(0, 0, int(opt.IntValue()))
+	}
+
+	guide, err := h.defaultGuide(ctx, i.GuildID)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get default guide for guild %s", i.GuildID)
+	}
+
+	var style *styles.Style
+
+	if opt, ok := options["number"]; ok {
+		if style = h.GuideRepo.Guide(guide).Get(ctx, strings.ToUpper(opt.StringValue())); style == nil {
+			return fmt.Sprintf("Style %s not found", opt.StringValue()), nil
+		}
+	}
+
+	challenge, err := h.startChallenge(ctx, i.GuildID, options["channel"].ChannelValue(nil).ID, guide, style,
+		challenges, now, endsAt)
+	if err != nil {
+		return "", errors.Wrap(err, "could not start challenge")
+	}
+
+	return fmt.Sprintf("Started the %s %s challenge in <#%s>", challenge.StyleNumber, challenge.StyleName,
+		challenge.ChannelID), nil
+}
+
+func (h *StylesHandler) handleChallengeEnd(ctx context.Context, challenges []dynamo.Challenge) (string, error) {
+	current := openChallenge(challenges)
+	if current == nil {
+		return "There is no challenge running", nil
+	}
+
+	if err := h.endChallenge(ctx, current, time.Now().UTC()); err != nil {
+		return "", errors.Wrap(err, "could not end challenge")
+	}
+
+	return fmt.Sprintf("Ended the %s %s challenge", current.StyleNumber, current.StyleName), nil
+}
+
+func (h *StylesHandler) handleChallengeCurrent(ctx context.Context, challenges []dynamo.Challenge) (string, error) {
+	current := openChallenge(challenges)
+	if current == nil {
+		return "There is no challenge running", nil
+	}
+
+	participants, err := h.challengeParticipants(ctx, current, time.Now().UTC())
+	if err != nil {
+		return "", errors.Wrap(err, "could not get challenge participants")
+	}
+
+	return fmt.Sprintf("Brew a %s %s before %s! %d brewers have taken part so far.", current.StyleNumber,
+		current.StyleName, formatChallengeDate(current.EndsAt), len(participants)), nil
+}
+
+func (h *StylesHandler) handleChallengeAuto(ctx context.Context, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) (string, error) {
+	options := optionsByName(opts)
+
+	settings, err := h.GuildSettingsRepo.Get(ctx, i.GuildID)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get settings for guild %s", i.GuildID)
+	}
+
+	if settings == nil {
+		settings = &dynamo.GuildSettings{GuildID: i.GuildID}
+	}
+
+	settings.AutoChallenge = options["enabled"].BoolValue()
+
+	if opt, ok := options["channel"]; ok {
+		settings.ChallengeChannelID = opt.ChannelValue(nil).ID
+	}
+
+	if settings.AutoChallenge && settings.ChallengeChannelID == "" {
+		return "Choose a channel to announce the challenges in", nil
+	}
+
+	if err := h.GuildSettingsRepo.Save(ctx, settings); err != nil {
+		return "", errors.Wrapf(err, "could not save settings for guild %s", i.GuildID)
+	}
+
+	if !settings.AutoChallenge {
+		return "Challenges will no longer start automatically", nil
+	}
+
+	return fmt.Sprintf("A random challenge will start in <#%s> each month", settings.ChallengeChannelID), nil
+}
+
+// RunChallenges is a scheduler job that ends every challenge that has run its course and, for guilds that have
+// enabled it, starts a random challenge running until the end of the month when none is running and none has been
+// started this month. Going by the newest challenge's start rather than whether one is running means a challenge
+// that is ended early is not replaced until the next month.
+func (h *StylesHandler) RunChallenges(ctx context.Context, now time.Time) error {
+	challenges, err := h.ChallengeRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get challenges")
+	}
+
+	running := map[string]bool{}
+	lastStartedAt := map[string]time.Time{}
+	byGuildID := map[string][]dynamo.Challenge{}
+
+	for i := range challenges {
+		challenge := &challenges[i]
+		byGuildID[challenge.GuildID] = append(byGuildID[challenge.GuildID], *challenge)
+
+		startsAt, err := time.Parse(time.RFC3339, challenge.StartsAt)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse time %s", challenge.StartsAt)
+		}
+
+		if startsAt.After(lastStartedAt[challenge.GuildID]) {
+			lastStartedAt[challenge.GuildID] = startsAt
+		}
+
+		if challenge.EndedAt != "" {
+			continue
+		}
+
+		endsAt, err := time.Parse(time.RFC3339, challenge.EndsAt)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse time %s", challenge.EndsAt)
+		}
+
+		if endsAt.After(now) {
+			running[challenge.GuildID] = true
+
+			continue
+		}
+
+		if err := h.endChallenge(ctx, challenge, endsAt); err != nil {
+			h.Logger.WithError(err).Errorf("could not end challenge for guild %s", challenge.GuildID)
+		}
+	}
+
+	settings, err := h.GuildSettingsRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get guild settings")
+	}
+
+	for _, setting := range settings {
+		if !setting.AutoChallenge || running[setting.GuildID] ||
+			!lastStartedAt[setting.GuildID].Before(startOfMonth(now)) {
+			continue
+		}
+
+		guide, err := h.defaultGuide(ctx, setting.GuildID)
+		if err != nil {
+			return errors.Wrapf(err, "could not get default guide for guild %s", setting.GuildID)
+		}
+
+		// A guild whose channel has gone away should not stop the other guilds' challenges from starting.
+		if _, err := h.startChallenge(ctx, setting.GuildID, setting.ChallengeChannelID, guide, nil,
+			byGuildID[setting.GuildID], now, endOfMonth(now)); err != nil {
+			h.Logger.WithError(err).Errorf("could not start challenge for guild %s", setting.GuildID)
+		}
+	}
+
+	return nil
+}
+
+// startChallenge starts and announces a challenge for the style, or for a random style that has not been the
+// subject of a previous challenge when style is nil.
+func (h *StylesHandler) startChallenge(ctx context.Context, guildID, channelID, guide string, style *styles.Style,
+	previous []dynamo.Challenge, now, endsAt time.Time,
+) (*dynamo.Challenge, error) {
+	repo := h.GuideRepo.Guide(guide)
+
+	if style == nil {
+		filter := styles.RandomFilter{ExcludeSpecialty: true}
+
+		for _, challenge := range previous {
+			filter.ExcludeNumbers = append(filter.ExcludeNumbers, challenge.StyleNumber)
+		}
+
+		// Start repeating styles once every one of them has had a challenge.
+		if style = repo.Random(ctx, filter, now.UnixNano()); style == nil {
+			style = repo.Random(ctx, styles.RandomFilter{ExcludeSpecialty: true}, now.UnixNano())
+		}
+
+		if style == nil {
+			return nil, errors.Errorf("guide %s has no styles to challenge", guide)
+		}
+	}
+
+	challenge := &dynamo.Challenge{
+		GuildID:     guildID,
+		StartsAt:    now.Format(time.RFC3339),
+		EndsAt:      endsAt.Format(time.RFC3339),
+		ChannelID:   channelID,
+		Guide:       guide,
+		StyleNumber: style.Number,
+		StyleName:   style.Name,
+	}
+
+	if err := h.ChallengeRepo.Save(ctx, challenge); err != nil {
+		return nil, errors.Wrap(err, "could not save challenge")
+	}
+
+	if err := h.Bot.SendMessage(channelID, challengeAnnouncement(style, endsAt)); err != nil {
+		return nil, errors.Wrap(err, "could not announce challenge")
+	}
+
+	return challenge, nil
+}
+
+// endChallenge records the participants of the challenge as of endedAt and posts the results.
+func (h *StylesHandler) endChallenge(ctx context.Context, challenge *dynamo.Challenge, endedAt time.Time) error {
+	participants, err := h.challengeParticipants(ctx, challenge, endedAt)
+	if err != nil {
+		return errors.Wrap(err, "could not get challenge participants")
+	}
+
+	challenge.Participants = participants
+	challenge.EndedAt = endedAt.Format(time.RFC3339)
+
+	if err := h.ChallengeRepo.Save(ctx, challenge); err != nil {
+		return errors.Wrap(err, "could not save challenge")
+	}
+
+	if err := h.Bot.SendMessage(challenge.ChannelID, challengeResults(challenge)); err != nil {
+		return errors.Wrap(err, "could not post challenge results")
+	}
+
+	return nil
+}
+
+// challengeParticipants totals the brews of the challenge's style logged between the start of the challenge and
+// until, ranked by number of brews and then volume.
+func (h *StylesHandler) challengeParticipants(ctx context.Context, challenge *dynamo.Challenge,
+	until time.Time,
+) ([]dynamo.Participant, error) {
+	brews, err := h.BrewRepo.GetCreatedAfter(ctx, challenge.StartsAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get brews")
+	}
+
+	repo := h.GuideRepo.Guide(challenge.Guide)
+	byUserID := map[string]*dynamo.Participant{}
+
+	for _, brew := range brews {
+		if brew.CreatedAt >= until.Format(time.RFC3339) || !brewedChallengeStyle(ctx, repo, challenge, brew) {
+			continue
+		}
+
+		participant, ok := byUserID[brew.UserID]
+		if !ok {
+			participant = &dynamo.Participant{UserID: brew.UserID}
+			byUserID[brew.UserID] = participant
+		}
+
+		participant.Username = brew.Username
+		participant.Count++
+		participant.Volume += brew.Amount
+	}
+
+	participants := make([]dynamo.Participant, 0, len(byUserID))
+	for _, participant := range byUserID {
+		participants = append(participants, *participant)
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		if participants[i].Count != participants[j].Count {
+			return participants[i].Count > participants[j].Count
+		}
+
+		return participants[i].Volume > participants[j].Volume
+	})
+
+	return participants, nil
+}
+
+// brewedChallengeStyle reports whether the brew is of the challenge's style. The guide may have been removed since
+// the challenge started, in which case only the style number and name are compared.
+func brewedChallengeStyle(ctx context.Context, repo styles.StyleRepo, challenge *dynamo.Challenge,
+	brew dynamo.Brew,
+) bool {
+	if repo == nil {
+		text := strings.TrimSpace(brew.Style)
+
+		return strings.EqualFold(text, challenge.StyleNumber) || strings.EqualFold(text, challenge.StyleName)
+	}
+
+	style := matchStyle(ctx, repo, brew.Style)
+
+	return style != nil && style.Number == challenge.StyleNumber
+}
+
+func challengeAnnouncement(style *styles.Style, endsAt time.Time) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("🏆 **Style Challenge: %s %s** (%s)\n", style.Number, style.Name,
+		style.Category))
+	builder.WriteString(truncate(style.OverallImpression, maxAnnouncementLength) + "\n")
+
+	vitalsLine := make([]string, 0, len(vitals))
+	for _, vital := range vitals {
+		vitalsLine = append(vitalsLine, fmt.Sprintf("%s %s", vital.name,
+			formatRange(vitalRange(style, vital.name), vital.precision)))
+	}
+
+	builder.WriteString(strings.Join(vitalsLine, " · ") + "\n")
+	builder.WriteString(fmt.Sprintf("Log a brew with `/brew log style:%s` before %s to take part!", style.Number,
+		endsAt.Format(challengeDateFormat)))
+
+	return builder.String()
+}
+
+func challengeResults(challenge *dynamo.Challenge) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("🏁 **The %s %s challenge is over!**\n", challenge.StyleNumber,
+		challenge.StyleName))
+
+	if len(challenge.Participants) == 0 {
+		builder.WriteString("Nobody brewed it this time. Maybe next time!")
+
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("%d brewers took part:\n", len(challenge.Participants)))
+
+	for n, participant := range challenge.Participants {
+		builder.WriteString(fmt.Sprintf("%d. %s - %d brews, %0.2f gallons\n", n+1, participant.Username,
+			participant.Count, participant.Volume))
+	}
+
+	return builder.String()
+}
+
+func challengeHistory(challenges []dynamo.Challenge) string {
+	var builder strings.Builder
+
+	for _, challenge := range challenges {
+		if challenge.EndedAt == "" {
+			continue
+		}
+
+		if builder.Len() == 0 {
+			builder.WriteString("Past challenges:\n")
+		}
+
+		builder.WriteString(fmt.Sprintf("**%s** %s %s - %d brewers\n", formatChallengeDate(challenge.StartsAt),
+			challenge.StyleNumber, challenge.StyleName, len(challenge.Participants)))
+
+		if strings.Count(builder.String(), "\n") > maxChallengeHistory {
+			break
+		}
+	}
+
+	if builder.Len() == 0 {
+		return "No challenges have finished yet"
+	}
+
+	return builder.String()
+}
+
+// openChallenge returns the challenge that has not ended yet, if there is one.
+func openChallenge(challenges []dynamo.Challenge) *dynamo.Challenge {
+	for i := range challenges {
+		if challenges[i].EndedAt == "" {
+			return &challenges[i]
+		}
+	}
+
+	return nil
+}
+
+func startOfMonth(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func endOfMonth(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func formatChallengeDate(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+
+	return t.Format(challengeDateFormat)
+}
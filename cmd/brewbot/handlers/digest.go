@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	digestSubCommandGroup = "digest"
+	setSubCommand         = "set"
+	disableSubCommand     = "disable"
+	defaultDigestSchedule = "0 9 * * 1"
+	digestLookback        = 7 * 24 * time.Hour
+	maxDigestBrews        = 15
+)
+
+func digestSubCommandGroupOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Name:        digestSubCommandGroup,
+		Description: "Configure the scheduled leaderboard digest",
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        setSubCommand,
+				Description: "Post a leaderboard digest to a channel on a schedule",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Channel to post the digest to",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						Required:     true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "schedule",
+						Description: "Cron schedule, e.g. 'CRON_TZ=America/Denver 0 9 * * 1' (default Mondays 9am UTC)",
+					},
+				},
+			},
+			{
+				Name:        disableSubCommand,
+				Description: "Stop posting the leaderboard digest",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	}
+}
+
+func (h *BrewsHandler) handleDigest(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	if !isAdmin(i) {
+		if err := respondToChannel(s, i, "Only server managers can configure the digest", true); err != nil {
+			return errors.Wrap(err, "could not respond with permission error")
+		}
+
+		return nil
+	}
+
+	switch opts[0].Name {
+	case setSubCommand:
+		return h.handleDigestSet(ctx, s, i, opts[0].Options)
+	case disableSubCommand:
+		return h.handleDigestDisable(ctx, s, i)
+	}
+
+	return nil
+}
+
+func (h *BrewsHandler) handleDigestSet(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	options := optionsByName(opts)
+
+	channel := options["channel"].ChannelValue(nil)
+
+	schedule := defaultDigestSchedule
+	if opt, ok := options["schedule"]; ok {
+		schedule = opt.StringValue()
+	}
+
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		if err := respondToChannel(s, i, fmt.Sprintf("Invalid schedule: %s", schedule), true); err != nil {
+			return errors.Wrap(err, "could not respond with invalid schedule error")
+		}
+
+		return nil
+	}
+
+	digest, err := h.DigestRepo.Get(ctx, i.GuildID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get digest for guild %s", i.GuildID)
+	}
+
+	if digest == nil {
+		digest = &dynamo.Digest{
+			GuildID: i.GuildID,
+		}
+	}
+
+	digest.ChannelID = channel.ID
+	digest.Schedule = schedule
+	digest.ScheduledAt = time.Now().UTC().Format(time.RFC3339)
+
+	if err := h.DigestRepo.Save(ctx, digest); err != nil {
+		return errors.Wrapf(err, "could not save digest for guild %s", i.GuildID)
+	}
+
+	message := fmt.Sprintf("Leaderboard digest will be posted to <#%s> on schedule `%s`", channel.ID, schedule)
+	if err := respondToChannel(s, i, message, true); err != nil {
+		return errors.Wrap(err, "could not respond with digest set message")
+	}
+
+	return nil
+}
+
+func (h *BrewsHandler) handleDigestDisable(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate,
+) error {
+	if err := h.DigestRepo.Delete(ctx, i.GuildID); err != nil {
+		return errors.Wrapf(err, "could not delete digest for guild %s", i.GuildID)
+	}
+
+	if err := respondToChannel(s, i, "Leaderboard digest disabled", true); err != nil {
+		return errors.Wrap(err, "could not respond with digest disabled message")
+	}
+
+	return nil
+}
+
+// PostDigests is a scheduler job that posts every digest whose schedule has come due since it was last posted.
+func (h *BrewsHandler) PostDigests(ctx context.Context, now time.Time) error {
+	digests, err := h.DigestRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get digests")
+	}
+
+	for i := range digests {
+		digest := &digests[i]
+
+		due, err := digestDue(digest, now)
+		if err != nil {
+			h.Logger.WithError(err).Errorf("could not check digest schedule for guild %s", digest.GuildID)
+
+			continue
+		}
+
+		if !due {
+			continue
+		}
+
+		if err := h.postDigest(ctx, digest, now); err != nil {
+			h.Logger.WithError(err).Errorf("could not post digest for guild %s", digest.GuildID)
+
+			continue
+		}
+	}
+
+	return nil
+}
+
+func digestDue(digest *dynamo.Digest, now time.Time) (bool, error) {
+	schedule, err := cron.ParseStandard(digest.Schedule)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not parse schedule %s", digest.Schedule)
+	}
+
+	last := digest.LastPostedAt
+	if last == "" {
+		last = digest.ScheduledAt
+	}
+
+	from, err := time.Parse(time.RFC3339, last)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not parse time %s", last)
+	}
+
+	return !schedule.Next(from).After(now), nil
+}
+
+func (h *BrewsHandler) postDigest(ctx context.Context, digest *dynamo.Digest, now time.Time) error {
+	entries, err := h.LeaderboardRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get leaderboard entries")
+	}
+
+	since := now.Add(-digestLookback)
+	if digest.LastPostedAt != "" {
+		if since, err = time.Parse(time.RFC3339, digest.LastPostedAt); err != nil {
+			return errors.Wrapf(err, "could not parse time %s", digest.LastPostedAt)
+		}
+	}
+
+	brews, err := h.BrewRepo.GetCreatedAfter(ctx, since.Format(time.RFC3339))
+	if err != nil {
+		return errors.Wrap(err, "could not get new brews")
+	}
+
+	standings := rankStandings(entries)
+
+	message, err := digestMessage(standings, standingsByUserID(digest.LastStandings), brews)
+	if err != nil {
+		return errors.Wrap(err, "could not build digest message")
+	}
+
+	if err := h.Bot.SendMessage(digest.ChannelID, message); err != nil {
+		return errors.Wrap(err, "could not send digest")
+	}
+
+	digest.LastPostedAt = now.Format(time.RFC3339)
+	digest.LastStandings = standings
+
+	if err := h.DigestRepo.Save(ctx, digest); err != nil {
+		return errors.Wrap(err, "could not save digest")
+	}
+
+	return nil
+}
+
+func digestMessage(standings []dynamo.Standing, previous map[string]dynamo.Standing,
+	brews []dynamo.Brew,
+) (string, error) {
+	var builder strings.Builder
+
+	builder.WriteString("**Leaderboard Digest**\n")
+
+	var (
+		totalCount  int
+		totalVolume float64
+	)
+
+	for _, standing := range standings {
+		totalCount += standing.Count
+		totalVolume += standing.Volume
+	}
+
+	if len(standings) == 0 {
+		builder.WriteString("No Brews yet!\n")
+	} else {
+		builder.WriteString("```\n")
+
+		if err := writeStandings(&builder, standings, previous); err != nil {
+			return "", errors.Wrap(err, "could not write standings")
+		}
+
+		builder.WriteString("```\n")
+	}
+
+	builder.WriteString("__**New Brews:**__\n")
+
+	if len(brews) == 0 {
+		builder.WriteString("Nothing new this time, get brewing!\n")
+	}
+
+	for i, brew := range brews {
+		if i == maxDigestBrews {
+			builder.WriteString(fmt.Sprintf("...and %d more\n", len(brews)-maxDigestBrews))
+
+			break
+		}
+
+		builder.WriteString(fmt.Sprintf("%s brewed %0.2f gallons of %s\n", brew.Username, brew.Amount, brew.Style))
+	}
+
+	builder.WriteString(fmt.Sprintf("__**Season Totals:**__\n%d batches, %0.2f gallons from %d brewers\n",
+		totalCount, totalVolume, len(standings)))
+
+	return builder.String(), nil
+}
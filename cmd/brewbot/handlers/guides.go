@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/styles"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+// GuideResolver picks the style guide an interaction should use, which lets every handler that looks up styles
+// honour the guild's default guide.
+type GuideResolver struct {
+	GuideRepo         styles.GuideRepo
+	GuildSettingsRepo dynamo.GuildSettingsRepo
+	DefaultGuide      string
+}
+
+// guide resolves the style guide for a command from the guide option, then the guild's default guide and finally
+// the configured default guide.
+func (r *GuideResolver) guide(ctx context.Context, guildID string,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) (styles.StyleRepo, error) {
+	name, err := r.guideName(ctx, guildID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GuideRepo.Guide(name), nil
+}
+
+// guideName resolves the name of the style guide for a command in the same order as guide.
+func (r *GuideResolver) guideName(ctx context.Context, guildID string,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) (string, error) {
+	if opt, ok := optionsByName(opts)[guideOption]; ok && r.GuideRepo.Guide(opt.StringValue()) != nil {
+		return opt.StringValue(), nil
+	}
+
+	name, err := r.defaultGuide(ctx, guildID)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get default guide for guild %s", guildID)
+	}
+
+	return name, nil
+}
+
+// defaultGuide returns the guild's default guide, or the configured default when the guild has not chosen one or
+// has chosen a guide that is no longer loaded.
+func (r *GuideResolver) defaultGuide(ctx context.Context, guildID string) (string, error) {
+	settings, err := r.GuildSettingsRepo.Get(ctx, guildID)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get settings for guild %s", guildID)
+	}
+
+	if settings == nil || r.GuideRepo.Guide(settings.StyleGuide) == nil {
+		return r.DefaultGuide, nil
+	}
+
+	return settings.StyleGuide, nil
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/pkg/errors"
+)
+
+// rankStandings orders the leaderboard entries by volume and assigns each one a rank. Users with the same volume are
+// ordered by ID so that they keep their ranks from one ranking to the next.
+func rankStandings(entries []dynamo.LeaderboardEntry) []dynamo.Standing {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Volume != entries[j].Volume {
+			return entries[i].Volume > entries[j].Volume
+		}
+
+		return entries[i].UserID < entries[j].UserID
+	})
+
+	standings := make([]dynamo.Standing, 0, len(entries))
+
+	for i, entry := range entries {
+		standings = append(standings, dynamo.Standing{
+			UserID:   entry.UserID,
+			Username: entry.Username,
+			Rank:     i + 1,
+			Count:    entry.Count,
+			Volume:   entry.Volume,
+		})
+	}
+
+	return standings
+}
+
+func standingsByUserID(standings []dynamo.Standing) map[string]dynamo.Standing {
+	m := make(map[string]dynamo.Standing, len(standings))
+
+	for _, standing := range standings {
+		m[standing.UserID] = standing
+	}
+
+	return m
+}
+
+// movement describes how a user's rank has changed since their previous standing.
+func movement(previous map[string]dynamo.Standing, current dynamo.Standing) string {
+	prev, ok := previous[current.UserID]
+
+	switch {
+	case !ok:
+		return "new"
+	case prev.Rank > current.Rank:
+		return fmt.Sprintf("▲%d", prev.Rank-current.Rank)
+	case prev.Rank < current.Rank:
+		return fmt.Sprintf("▼%d", current.Rank-prev.Rank)
+	default:
+		return "-"
+	}
+}
+
+// volumeDelta describes how much a user's volume has changed since their previous standing.
+func volumeDelta(previous map[string]dynamo.Standing, current dynamo.Standing) string {
+	delta := current.Volume - previous[current.UserID].Volume
+	if delta == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%+0.2f", delta)
+}
+
+// writeStandings writes the standings as a table along with each user's movement since the previous standings.
+func writeStandings(w io.Writer, standings []dynamo.Standing, previous map[string]dynamo.Standing) error {
+	writer := tabwriter.NewWriter(w, 0, 5, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "\tName\tCount\tGallons\tMove\t\t")
+
+	for _, standing := range standings {
+		fmt.Fprintf(writer, "%d\t%s\t%d\t%6.02f\t%s\t%s\t\n", standing.Rank, standing.Username, standing.Count,
+			standing.Volume, movement(previous, standing), volumeDelta(previous, standing))
+	}
+
+	if err := writer.Flush(); err != nil {
+		return errors.Wrap(err, "could not flush to writer")
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const notifySubCommand = "notify"
+
+func notifySubCommandOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Name:        notifySubCommand,
+		Description: "Choose which leaderboard notifications BrewBot sends you",
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "overtaken",
+				Description: "DM me when someone passes me on the leaderboard",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func (h *BrewsHandler) handleNotify(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	user *discordgo.User, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	overtaken := opts[0].BoolValue()
+
+	preference, err := h.NotificationPreferenceRepo.Get(ctx, user.ID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get notification preference for user %s", user.ID)
+	}
+
+	if preference == nil {
+		preference = &dynamo.NotificationPreference{
+			UserID: user.ID,
+		}
+	}
+
+	preference.Overtaken = overtaken
+
+	if err := h.NotificationPreferenceRepo.Save(ctx, preference); err != nil {
+		return errors.Wrapf(err, "could not save notification preference for user %s", user.ID)
+	}
+
+	message := "You will no longer be notified when you are overtaken"
+	if overtaken {
+		message = "You will be notified when you are overtaken"
+	}
+
+	if err := respondToChannel(s, i, message, true); err != nil {
+		return errors.Wrap(err, "could not respond with notify success message")
+	}
+
+	return nil
+}
+
+// notifyOvertaken DMs each opted in user that the given user has just passed on the leaderboard. A user only counts as
+// passed when the given user now has strictly more volume than them. Failures are logged rather than returned so that
+// a closed DM channel never fails the brew that caused the change.
+func (h *BrewsHandler) notifyOvertaken(ctx context.Context, userID string, before, after []dynamo.Standing) {
+	previous := standingsByUserID(before)
+	current := standingsByUserID(after)
+
+	passer, ok := current[userID]
+	if !ok {
+		return
+	}
+
+	passerBefore, wasRanked := previous[userID]
+
+	for _, standing := range after {
+		if standing.UserID == userID || standing.Rank <= passer.Rank || standing.Volume >= passer.Volume {
+			continue
+		}
+
+		prev, ok := previous[standing.UserID]
+		if !ok || (wasRanked && prev.Rank > passerBefore.Rank) {
+			continue
+		}
+
+		if err := h.notifyUser(ctx, standing.UserID, passer); err != nil {
+			h.Logger.WithError(err).Warnf("could not notify user %s that they were overtaken", standing.UserID)
+		}
+	}
+}
+
+func (h *BrewsHandler) notifyUser(ctx context.Context, userID string, passer dynamo.Standing) error {
+	preference, err := h.NotificationPreferenceRepo.Get(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get notification preference for user %s", userID)
+	}
+
+	if preference == nil || !preference.Overtaken {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	if preference.LastNotifiedAt != "" {
+		last, err := time.Parse(time.RFC3339, preference.LastNotifiedAt)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse time %s", preference.LastNotifiedAt)
+		}
+
+		if now.Sub(last) < h.NotificationCooldown {
+			return nil
+		}
+	}
+
+	message := fmt.Sprintf("%s just passed you on the leaderboard with %0.2f gallons!", passer.Username,
+		passer.Volume)
+
+	if err := h.Bot.SendDirectMessage(userID, message); err != nil {
+		return errors.Wrap(err, "could not send overtaken message")
+	}
+
+	preference.LastNotifiedAt = now.Format(time.RFC3339)
+
+	if err := h.NotificationPreferenceRepo.Save(ctx, preference); err != nil {
+		return errors.Wrapf(err, "could not save notification preference for user %s", userID)
+	}
+
+	return nil
+}
@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/styles"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const (
+	quizSubCommand      = "quiz"
+	quizboardSubCommand = "quizboard"
+	quizComponent       = "quiz"
+	maxQuizboardEntries = 10
+	maxButtonLabel      = 80
+)
+
+func (h *StylesHandler) handleQuiz(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	guide string, repo styles.StyleRepo,
+) error {
+	//nolint: gosec
+	seed := rand.Int63n(maxSeed)
+
+	quiz := repo.Quiz(ctx, seed)
+	if quiz == nil {
+		if err := respondToChannel(s, i, "There are not enough styles in this guide for a quiz", true); err != nil {
+			return errors.Wrap(err, "could not respond with no quiz error")
+		}
+
+		return nil
+	}
+
+	issued := &dynamo.Quiz{
+		ID:          i.ID,
+		GuildID:     i.GuildID,
+		UserID:      i.Member.User.ID,
+		Guide:       guide,
+		StyleNumber: quiz.Style.Number,
+		StyleName:   quiz.Style.Name,
+		Answer:      quiz.Answer,
+		Picked:      -1,
+	}
+
+	for _, choice := range quiz.Choices {
+		issued.Choices = append(issued.Choices, dynamo.QuizChoice{Number: choice.Number, Name: choice.Name})
+	}
+
+	if err := h.QuizRepo.Save(ctx, issued); err != nil {
+		return errors.Wrap(err, "could not save quiz")
+	}
+
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:    fmt.Sprintf("<@%s>, which style is this?", i.Member.User.ID),
+			Embeds:     []*discordgo.MessageEmbed{quizEmbed(guide, quiz)},
+			Components: quizButtons(issued),
+		},
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not respond with quiz")
+	}
+
+	return nil
+}
+
+// QuizComponentHandler reveals the answer to a quiz when the user who asked for it picks one of the choices, and
+// records the answer on the quiz leaderboard. The buttons carry only the quiz's ID and the choice in their custom ID;
+// the quiz itself, answer included, is kept by the bot, so it cannot be worked out from the message and does not
+// change when its guide is reloaded. A quiz can only be answered once.
+func (h *StylesHandler) QuizComponentHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx := context.Background()
+
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 3 { //nolint: gomnd
+		return errors.Errorf("invalid quiz custom ID %s", i.MessageComponentData().CustomID)
+	}
+
+	choice, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return errors.Wrapf(err, "invalid quiz choice %s", parts[2])
+	}
+
+	quiz, err := h.QuizRepo.Get(ctx, parts[1])
+	if err != nil {
+		return errors.Wrapf(err, "could not get quiz %s", parts[1])
+	}
+
+	if quiz == nil || choice < 0 || choice >= len(quiz.Choices) {
+		response := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    "This quiz is no longer available",
+				Embeds:     []*discordgo.MessageEmbed{},
+				Components: []discordgo.MessageComponent{},
+			},
+		}
+
+		if err := s.InteractionRespond(i.Interaction, response); err != nil {
+			return errors.Wrap(err, "could not respond with quiz not found error")
+		}
+
+		return nil
+	}
+
+	if i.Member.User.ID != quiz.UserID {
+		if err := respondToChannel(s, i, "This quiz is someone else's, start your own with /styles quiz",
+			true); err != nil {
+			return errors.Wrap(err, "could not respond with wrong user error")
+		}
+
+		return nil
+	}
+
+	answered, err := h.QuizRepo.Answer(ctx, quiz, choice)
+	if err != nil {
+		return errors.Wrapf(err, "could not answer quiz %s", quiz.ID)
+	}
+
+	if !answered {
+		if err := respondToChannel(s, i, "You have already answered this quiz", true); err != nil {
+			return errors.Wrap(err, "could not respond with already answered error")
+		}
+
+		return nil
+	}
+
+	score, err := h.recordQuizAnswer(ctx, i, choice == quiz.Answer)
+	if err != nil {
+		return errors.Wrap(err, "could not record quiz answer")
+	}
+
+	content := fmt.Sprintf("✅ <@%s> got it! It's %s (%s).", quiz.UserID, quiz.StyleName, quiz.StyleNumber)
+	if choice != quiz.Answer {
+		content = fmt.Sprintf("❌ <@%s> picked %s, but it's %s (%s).", quiz.UserID, quiz.Choices[choice].Name,
+			quiz.StyleName, quiz.StyleNumber)
+	}
+
+	// The description is kept from the quiz as it was asked rather than rebuilt from the guide, which may have
+	// changed since.
+	embed := &discordgo.MessageEmbed{}
+	if len(i.Message.Embeds) > 0 {
+		embed = i.Message.Embeds[0]
+	}
+
+	embed.Title = fmt.Sprintf("%s (%s)", quiz.StyleName, quiz.StyleNumber)
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("%s · %d/%d correct · Streak %d", quiz.Guide, score.Correct, score.Answered, score.Streak),
+	}
+
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: quizButtons(quiz),
+		},
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not respond with quiz answer")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) recordQuizAnswer(ctx context.Context, i *discordgo.InteractionCreate,
+	correct bool,
+) (*dynamo.QuizScore, error) {
+	score, err := h.QuizScoreRepo.Get(ctx, i.GuildID, i.Member.User.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get quiz score for user %s", i.Member.User.ID)
+	}
+
+	if score == nil {
+		score = &dynamo.QuizScore{GuildID: i.GuildID, UserID: i.Member.User.ID}
+	}
+
+	score.Username = i.Member.User.Username
+	if i.Member.Nick != "" {
+		score.Username = i.Member.Nick
+	}
+
+	score.Answered++
+
+	if correct {
+		score.Correct++
+		score.Streak++
+	} else {
+		score.Streak = 0
+	}
+
+	if score.Streak > score.BestStreak {
+		score.BestStreak = score.Streak
+	}
+
+	if err := h.QuizScoreRepo.Save(ctx, score); err != nil {
+		return nil, errors.Wrapf(err, "could not save quiz score for user %s", i.Member.User.ID)
+	}
+
+	return score, nil
+}
+
+func (h *StylesHandler) handleQuizboard(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	scores, err := h.QuizScoreRepo.GetByGuildID(ctx, i.GuildID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get quiz scores for guild %s", i.GuildID)
+	}
+
+	if len(scores) == 0 {
+		if err := respondToChannel(s, i, "Nobody has taken the quiz yet, try /styles quiz", false); err != nil {
+			return errors.Wrap(err, "could not respond with empty quizboard")
+		}
+
+		return nil
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Correct != scores[j].Correct {
+			return scores[i].Correct > scores[j].Correct
+		}
+
+		return scores[i].Answered < scores[j].Answered
+	})
+
+	var builder strings.Builder
+
+	builder.WriteString("Quiz leaderboard:\n")
+
+	for n, score := range scores {
+		if n == maxQuizboardEntries {
+			break
+		}
+
+		builder.WriteString(fmt.Sprintf("%d. %s - %d/%d correct (%0.0f%%), best streak %d\n", n+1, score.Username,
+			score.Correct, score.Answered, 100*float64(score.Correct)/float64(score.Answered), score.BestStreak))
+	}
+
+	if err := respondToChannel(s, i, builder.String(), false); err != nil {
+		return errors.Wrap(err, "could not respond with quizboard")
+	}
+
+	return nil
+}
+
+// quizEmbed describes the style of a quiz by its vitals, aroma and appearance, or its overall impression when the
+// guide does not describe those, with anything naming it redacted.
+func quizEmbed(guide string, quiz *styles.Quiz) *discordgo.MessageEmbed {
+	style := quiz.Style
+
+	embed := &discordgo.MessageEmbed{
+		Title: "Which style is this?",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: guide,
+		},
+	}
+
+	for _, vital := range vitals {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   vital.name,
+			Value:  formatRange(vitalRange(&style, vital.name), vital.precision),
+			Inline: true,
+		})
+	}
+
+	sections := []infoSection{
+		{name: "Aroma", text: style.Aroma},
+		{name: "Appearance", text: style.Appearance},
+	}
+
+	if style.Aroma == "" && style.Appearance == "" {
+		sections = []infoSection{{name: "Overall Impression", text: style.OverallImpression}}
+	}
+
+	for _, section := range sections {
+		if section.text == "" {
+			continue
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  section.name,
+			Value: truncate(style.Redact(section.text), maxFieldLength),
+		})
+	}
+
+	return embed
+}
+
+// quizButtons renders a button per choice. Once a choice has been picked the buttons are disabled, with the answer
+// shown in green and a wrong pick in red.
+func quizButtons(quiz *dynamo.Quiz) []discordgo.MessageComponent {
+	buttons := make([]discordgo.MessageComponent, 0, len(quiz.Choices))
+	picked := quiz.AnsweredAt != ""
+
+	for n, choice := range quiz.Choices {
+		button := discordgo.Button{
+			Label:    truncate(fmt.Sprintf("%s %s", choice.Number, choice.Name), maxButtonLabel),
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("%s:%s:%d", quizComponent, quiz.ID, n),
+			Disabled: picked,
+		}
+
+		if picked {
+			switch n {
+			case quiz.Answer:
+				button.Style = discordgo.SuccessButton
+			case quiz.Picked:
+				button.Style = discordgo.DangerButton
+			default:
+				button.Style = discordgo.SecondaryButton
+			}
+		}
+
+		buttons = append(buttons, button)
+	}
+
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/pkg/errors"
+)
+
+// RoleAwards configures the Discord roles that are awarded for leaderboard standings.
+type RoleAwards struct {
+	TopBrewerRoleID string
+	Milestones      []Milestone
+}
+
+func (a RoleAwards) enabled() bool {
+	return a.TopBrewerRoleID != "" || len(a.Milestones) > 0
+}
+
+// Milestone awards a role to everyone who has brewed at least Volume gallons this season.
+type Milestone struct {
+	Volume float64
+	RoleID string
+}
+
+// ReconcileRoles brings the awarded roles in line with the current leaderboard. It does nothing when no roles are
+// configured.
+func (h *BrewsHandler) ReconcileRoles(ctx context.Context) error {
+	if !h.RoleAwards.enabled() {
+		return nil
+	}
+
+	leaderboardEntries, err := h.LeaderboardRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get leaderboard entries")
+	}
+
+	return h.reconcileRoles(rankStandings(leaderboardEntries))
+}
+
+func (h *BrewsHandler) reconcileRoles(standings []dynamo.Standing) error {
+	if !h.RoleAwards.enabled() {
+		return nil
+	}
+
+	desired := map[string][]string{}
+
+	if h.RoleAwards.TopBrewerRoleID != "" {
+		desired[h.RoleAwards.TopBrewerRoleID] = []string{}
+
+		for _, standing := range standings {
+			if standing.Volume == standings[0].Volume {
+				desired[h.RoleAwards.TopBrewerRoleID] = append(desired[h.RoleAwards.TopBrewerRoleID],
+					standing.UserID)
+			}
+		}
+	}
+
+	for _, milestone := range h.RoleAwards.Milestones {
+		if _, ok := desired[milestone.RoleID]; !ok {
+			desired[milestone.RoleID] = []string{}
+		}
+
+		for _, standing := range standings {
+			if standing.Volume >= milestone.Volume {
+				desired[milestone.RoleID] = append(desired[milestone.RoleID], standing.UserID)
+			}
+		}
+	}
+
+	if err := h.RoleManager.Reconcile(desired); err != nil {
+		return errors.Wrap(err, "could not reconcile roles")
+	}
+
+	return nil
+}
@@ -1,27 +1,61 @@
 package handlers
 
 import (
+	"context"
 	"time"
 
 	"github.com/benjaminbartels/brewbot/internal/dynamo"
 	"github.com/benjaminbartels/brewbot/internal/platform/discord"
+	"github.com/benjaminbartels/brewbot/internal/platform/scheduler"
 	"github.com/benjaminbartels/brewbot/internal/styles"
+	"github.com/benjaminbartels/brewbot/internal/untappd"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-func NewAPI(bot *discord.Bot, brewRepo dynamo.BrewRepo, leaderboardRepo dynamo.LeaderboardRepo,
-	stylesRepo styles.StyleRepo, leaderboardCutoff time.Time, logger *logrus.Logger,
+func NewAPI(bot *discord.Bot, sched *scheduler.Scheduler, brewRepo dynamo.BrewRepo,
+	leaderboardRepo dynamo.LeaderboardRepo, digestRepo dynamo.DigestRepo, snapshotRepo dynamo.SnapshotRepo,
+	notificationPreferenceRepo dynamo.NotificationPreferenceRepo, teamRepo dynamo.TeamRepo,
+	guildSettingsRepo dynamo.GuildSettingsRepo, challengeRepo dynamo.ChallengeRepo,
+	quizScoreRepo dynamo.QuizScoreRepo, quizRepo dynamo.QuizRepo, venueRepo dynamo.VenueRepo,
+	scrapeCache *untappd.Cache,
+	guideRepo styles.GuideRepo, defaultGuide string,
+	leaderboardCutoff time.Time, notificationCooldown, tapListInterval time.Duration, roleAwards RoleAwards,
+	logger *logrus.Logger,
 ) error {
+	guideResolver := &GuideResolver{
+		GuideRepo:         guideRepo,
+		GuildSettingsRepo: guildSettingsRepo,
+		DefaultGuide:      defaultGuide,
+	}
+
 	brewsHandler := &BrewsHandler{
 		BrewRepo:          brewRepo,
 		LeaderboardRepo:   leaderboardRepo,
+		DigestRepo:        digestRepo,
+		SnapshotRepo:      snapshotRepo,
+		TeamRepo:          teamRepo,
 		LeaderboardCutoff: leaderboardCutoff,
+		Bot:               bot,
 		Logger:            logger,
+
+		NotificationPreferenceRepo: notificationPreferenceRepo,
+		NotificationCooldown:       notificationCooldown,
+
+		RoleManager: discord.NewRoleManager(bot),
+		RoleAwards:  roleAwards,
+
+		GuideResolver: guideResolver,
 	}
 
 	stylesHandler := &StylesHandler{
-		StyleRepo: stylesRepo,
+		GuideResolver: guideResolver,
+		BrewRepo:      brewRepo,
+		ChallengeRepo: challengeRepo,
+		QuizScoreRepo: quizScoreRepo,
+		QuizRepo:      quizRepo,
+		Bot:           bot,
+		Logger:        logger,
 	}
 
 	if err := bot.AddCommand(BrewCommand()); err != nil {
@@ -36,11 +70,29 @@ func NewAPI(bot *discord.Bot, brewRepo dynamo.BrewRepo, leaderboardRepo dynamo.L
 		return errors.Wrap(err, "could not add 'untapdd' command")
 	}
 
-	untapddHandler := NewUntapddHandler()
+	untapddHandler := NewUntapddHandler(guideResolver, brewRepo, venueRepo, tapListInterval, scrapeCache, bot,
+		logger)
 
 	bot.AddHandler("brew", brewsHandler.BrewHandler)
 	bot.AddHandler("styles", stylesHandler.StyleHandler)
 	bot.AddHandler("untapdd", untapddHandler.UntapddHandler)
 
+	bot.AddAutocompleteHandler("styles", stylesHandler.StyleAutocompleteHandler)
+	bot.AddAutocompleteHandler("untapdd", untapddHandler.UntapddAutocompleteHandler)
+
+	bot.AddComponentHandler(styleInfoComponent, stylesHandler.StyleInfoComponentHandler)
+	bot.AddComponentHandler(quizComponent, stylesHandler.QuizComponentHandler)
+
+	bot.AddComponentHandler(brewDetailsComponent, brewsHandler.BrewDetailsComponentHandler)
+	bot.AddModalHandler(brewDetailsModal, brewsHandler.BrewDetailsModalHandler)
+
+	sched.AddJob("digest", brewsHandler.PostDigests)
+	sched.AddJob("challenges", stylesHandler.RunChallenges)
+	sched.AddJob("taplists", untapddHandler.WatchTapLists)
+
+	if err := brewsHandler.ReconcileRoles(context.Background()); err != nil {
+		logger.WithError(err).Warn("could not reconcile leaderboard roles")
+	}
+
 	return nil
 }
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/benjaminbartels/brewbot/internal/styles"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const (
+	styleInfoComponent   = "styleinfo"
+	maxDescriptionLength = 4096
+)
+
+type infoSection struct {
+	name string
+	text string
+}
+
+func (h *StylesHandler) handleInfo(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, guide string, repo styles.StyleRepo,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	number := optionsByName(opts)["number"].StringValue()
+
+	style := repo.Get(ctx, strings.ToUpper(number))
+	if style == nil {
+		if err := respondToChannel(s, i, fmt.Sprintf("Style %s not found", number), true); err != nil {
+			return errors.Wrap(err, "could not respond with not found error")
+		}
+
+		return nil
+	}
+
+	embed, components := infoPage(guide, style, 0)
+
+	if err := respondWithPage(s, i, discordgo.InteractionResponseChannelMessageWithSource, embed, components,
+		true); err != nil {
+		return errors.Wrap(err, "could not respond with style info")
+	}
+
+	return nil
+}
+
+// StyleInfoComponentHandler pages through the sections of a style when one of the navigation buttons below a
+// /styles info response is clicked. The buttons carry the guide, style number and target page in their custom ID.
+func (h *StylesHandler) StyleInfoComponentHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx := context.Background()
+
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 4 { //nolint: gomnd
+		return errors.Errorf("invalid style info custom ID %s", i.MessageComponentData().CustomID)
+	}
+
+	guide, number := parts[1], parts[2]
+
+	page, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return errors.Wrapf(err, "invalid style info page %s", parts[3])
+	}
+
+	var style *styles.Style
+	if repo := h.GuideRepo.Guide(guide); repo != nil {
+		style = repo.Get(ctx, number)
+	}
+
+	if style == nil {
+		response := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    fmt.Sprintf("Style %s is no longer in the %s guide", number, guide),
+				Embeds:     []*discordgo.MessageEmbed{},
+				Components: []discordgo.MessageComponent{},
+			},
+		}
+
+		if err := s.InteractionRespond(i.Interaction, response); err != nil {
+			return errors.Wrap(err, "could not respond with not found error")
+		}
+
+		return nil
+	}
+
+	embed, components := infoPage(guide, style, page)
+
+	if err := respondWithPage(s, i, discordgo.InteractionResponseUpdateMessage, embed, components,
+		true); err != nil {
+		return errors.Wrap(err, "could not respond with style info page")
+	}
+
+	return nil
+}
+
+// infoPage renders one page of a style. The first page is an overview of the category, vitals and overall
+// impression and each following page holds one section, with sections too long for a single embed spread over
+// several pages.
+func infoPage(guide string, style *styles.Style, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	pages := infoSectionPages(style)
+
+	if page < 0 {
+		page = 0
+	}
+
+	if page > len(pages) {
+		page = len(pages)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s (%s)", style.Name, style.Number),
+		Color: style.Color(),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s · Page %d/%d", guide, page+1, len(pages)+1),
+		},
+	}
+
+	if page == 0 {
+		embed.Description = truncate(style.OverallImpression, maxDescriptionLength)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Category",
+			Value: fmt.Sprintf("%s (%s)", style.Category, style.CategoryNumber),
+		})
+
+		for _, vital := range vitals {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   vital.name,
+				Value:  formatRange(vitalRange(style, vital.name), vital.precision),
+				Inline: true,
+			})
+		}
+	} else {
+		section := pages[page-1]
+		embed.Title = fmt.Sprintf("%s (%s) - %s", style.Name, style.Number, section.name)
+		embed.Description = section.text
+	}
+
+	customID := func(target int) string {
+		return fmt.Sprintf("%s:%s:%s:%d", styleInfoComponent, guide, style.Number, target)
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: customID(page - 1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: customID(page + 1),
+					Disabled: page == len(pages),
+				},
+			},
+		},
+	}
+
+	return embed, components
+}
+
+// infoSectionPages returns the non-empty sections of a style, splitting any section that does not fit in an embed
+// description into numbered parts.
+func infoSectionPages(style *styles.Style) []infoSection {
+	sections := []infoSection{
+		{name: "Entry Instructions", text: style.EntryInstructions},
+		{name: "Aroma", text: style.Aroma},
+		{name: "Appearance", text: style.Appearance},
+		{name: "Flavor", text: style.Flavor},
+		{name: "Mouthfeel", text: style.Mouthfeel},
+		{name: "Comments", text: style.Comments},
+		{name: "History", text: style.History},
+		{name: "Characteristic Ingredients", text: style.CharacteristicIngredients},
+		{name: "Style Comparison", text: style.StyleComparison},
+		{name: "Commercial Examples", text: style.CommercialExamples},
+	}
+
+	pages := make([]infoSection, 0, len(sections))
+
+	for _, section := range sections {
+		if strings.TrimSpace(section.text) == "" {
+			continue
+		}
+
+		parts := splitText(section.text, maxDescriptionLength)
+
+		for n, part := range parts {
+			name := section.name
+			if len(parts) > 1 {
+				name = fmt.Sprintf("%s (%d/%d)", section.name, n+1, len(parts))
+			}
+
+			pages = append(pages, infoSection{name: name, text: part})
+		}
+	}
+
+	return pages
+}
+
+// splitText splits text into chunks of at most n runes, breaking at the last line break in each chunk, or the last
+// whitespace if there is none, so lines and words are kept whole. A single word longer than n is broken mid-word.
+func splitText(text string, n int) []string {
+	runes := []rune(strings.TrimSpace(text))
+	chunks := []string{}
+
+	for len(runes) > n {
+		cut := lastIndex(runes[:n+1], func(r rune) bool { return r == '\n' })
+		if cut <= 0 {
+			cut = lastIndex(runes[:n+1], unicode.IsSpace)
+		}
+
+		if cut <= 0 {
+			cut = n
+		}
+
+		chunks = append(chunks, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+
+	return append(chunks, string(runes))
+}
+
+func lastIndex(runes []rune, f func(rune) bool) int {
+	for j := len(runes) - 1; j >= 0; j-- {
+		if f(runes[j]) {
+			return j
+		}
+	}
+
+	return -1
+}
+
+func respondWithPage(s *discordgo.Session, i *discordgo.InteractionCreate,
+	responseType discordgo.InteractionResponseType, embed *discordgo.MessageEmbed,
+	components []discordgo.MessageComponent, isEphemeral bool,
+) error {
+	response := &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	}
+
+	if isEphemeral {
+		//nolint: gomnd
+		response.Data.Flags = 1 << 6
+	}
+
+	if err := s.InteractionRespond(i.Interaction, response); err != nil {
+		return errors.Wrap(err, "could not send interaction response")
+	}
+
+	return nil
+}
@@ -3,48 +3,310 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/platform/discord"
 	"github.com/benjaminbartels/brewbot/internal/styles"
 	"github.com/bwmarrin/discordgo"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 const (
-	styleCommand     = "styles"
-	randomSubCommand = "rand"
-	infoSubCommand   = "info"
+	styleCommand         = "styles"
+	randomSubCommand     = "rand"
+	infoSubCommand       = "info"
+	searchSubCommand     = "search"
+	findSubCommand       = "find"
+	maxFindResults       = 25
+	compareSubCommand    = "compare"
+	maxFieldLength       = 1024
+	maxSearchResults     = 10
+	maxChoices           = 25
+	maxSeed              = 1000000
+	guideSubCommand      = "guide"
+	categoriesSubCommand = "categories"
+	tagsSubCommand       = "tags"
+	checkSubCommand      = "check"
+	similarSubCommand    = "similar"
+	suggestSubCommand    = "suggest"
+	maxSimilar           = 10
+	maxSuggestions       = 5
+	maxFits              = 5
+	guideOption          = "guide"
 )
 
 type StylesHandler struct {
-	StyleRepo styles.StyleRepo
+	*GuideResolver
+	BrewRepo      dynamo.BrewRepo
+	ChallengeRepo dynamo.ChallengeRepo
+	QuizScoreRepo dynamo.QuizScoreRepo
+	QuizRepo      dynamo.QuizRepo
+	Bot           *discord.Bot
+	Logger        *logrus.Logger
 }
 
+// StyleCommand builds the styles command. Every subcommand that reads a guide accepts a guide option choosing
+// between the loaded guides, falling back to the guild's default guide. Guide names are autocompleted rather than
+// fixed choices so that guides added while the bot is running can be chosen without registering the command again.
 func StyleCommand() *discordgo.ApplicationCommand {
-	return &discordgo.ApplicationCommand{
+	command := &discordgo.ApplicationCommand{
 		Name:        styleCommand,
 		Description: "Issues style realted commands to BrewBot",
 		Options: []*discordgo.ApplicationCommandOption{
 			{
 				Name:        randomSubCommand,
-				Description: "Pick a random beer style to brew from the 2021 BJCP style guide",
+				Description: "Pick a random style to brew from the style guide",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "category",
+						Description: "Only pick from this category name or number",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "tags",
+						Description: "Only pick styles with all of these comma separated tags, e.g. lagered,dark-color",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "max_abv",
+						Description: "Only pick styles that can be brewed at or below this ABV",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "exclude_specialty",
+						Description: "Skip specialty styles",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "exclude_brewed",
+						Description: "Skip styles you have already brewed",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "seed",
+						Description: "Seed to reproduce a previous pick",
+					},
+				},
 			},
 			{
 				Name:        infoSubCommand,
-				Description: "Get information about a beer style from the 2021 BJCP style guide",
+				Description: "Get information about a style from the style guide",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "number",
+						Description:  "Style number",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        findSubCommand,
+				Description: "Find styles whose vital statistics overlap the given ranges",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     findOptions(),
+			},
+			{
+				Name:        compareSubCommand,
+				Description: "Compare two styles from the style guide side by side",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "a",
+						Description:  "Style number of the first style",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "b",
+						Description:  "Style number of the second style",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        searchSubCommand,
+				Description: "Search the style guide by name, category, tag or commercial example",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Options: []*discordgo.ApplicationCommandOption{
 					{
 						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "number",
-						Description: "BJCP category number",
+						Name:        "query",
+						Description: "What to search for",
 						Required:    true,
 					},
 				},
 			},
+			{
+				Name:        similarSubCommand,
+				Description: "List the styles most similar to a style",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "number",
+						Description:  "Style number",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        suggestSubCommand,
+				Description: "Suggest styles you have not brewed yet that are close to your favorites",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        checkSubCommand,
+				Description: "Check a recipe's vital statistics against a style",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     checkOptions(),
+			},
+			{
+				Name:        categoriesSubCommand,
+				Description: "List every category in the style guide with its styles",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        tagsSubCommand,
+				Description: "List the styles carrying a tag",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "tag",
+						Description:  "Tag to list the styles of, e.g. smoke or north-america",
+						Required:     true,
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Name:        quizSubCommand,
+				Description: "Identify a style from its description",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+		},
+	}
+
+	for _, subcommand := range command.Options {
+		subcommand.Options = append(subcommand.Options, &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         guideOption,
+			Description:  "Style guide to use instead of the server default",
+			Autocomplete: true,
+		})
+	}
+
+	command.Options = append(command.Options, &discordgo.ApplicationCommandOption{
+		Name:        guideSubCommand,
+		Description: "Show or set the server's default style guide",
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "name",
+				Description:  "Style guide to make the default",
+				Autocomplete: true,
+			},
 		},
+	})
+
+	command.Options = append(command.Options, &discordgo.ApplicationCommandOption{
+		Name:        quizboardSubCommand,
+		Description: "Show the quiz leaderboard",
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+	})
+
+	// Challenges always use the server's default guide, so the group is added after the guide options.
+	command.Options = append(command.Options, challengeSubCommandGroupOption())
+
+	return command
+}
+
+// vitals lists the vital statistics in display order along with the precision they are shown with.
+//
+//nolint:gochecknoglobals
+var vitals = []struct {
+	name      string
+	precision int
+}{
+	{name: "ABV", precision: 1},
+	{name: "IBU", precision: 0},
+	{name: "SRM", precision: 0},
+	{name: "OG", precision: 3},
+	{name: "FG", precision: 3},
+}
+
+func findOptions() []*discordgo.ApplicationCommandOption {
+	options := make([]*discordgo.ApplicationCommandOption, 0, len(vitals)*2) //nolint: gomnd
+
+	for _, vital := range vitals {
+		options = append(options,
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "min_" + strings.ToLower(vital.name),
+				Description: "Minimum " + vital.name,
+			},
+			&discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "max_" + strings.ToLower(vital.name),
+				Description: "Maximum " + vital.name,
+			},
+		)
 	}
+
+	return options
+}
+
+func checkOptions() []*discordgo.ApplicationCommandOption {
+	options := []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "number",
+			Description:  "Style number to check against",
+			Required:     true,
+			Autocomplete: true,
+		},
+	}
+
+	// Only the gravities are required. The IBU and SRM mean nothing for meads and ciders, and the ABV can be estimated
+	// from the gravities. Discord requires optional options to come after the required ones.
+	for _, name := range []string{"OG", "FG"} {
+		options = append(options, &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        strings.ToLower(name),
+			Description: "The recipe's " + name,
+			Required:    true,
+		})
+	}
+
+	for _, name := range []string{"IBU", "SRM"} {
+		options = append(options, &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        strings.ToLower(name),
+			Description: "The recipe's " + name + ", left unchecked when not given",
+		})
+	}
+
+	return append(options, &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "abv",
+		Description: "The recipe's ABV, estimated from the OG and FG when not given",
+	})
 }
 
 func (h *StylesHandler) StyleHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
@@ -53,13 +315,69 @@ func (h *StylesHandler) StyleHandler(s *discordgo.Session, i *discordgo.Interact
 	user := i.Member.User
 	opts := i.ApplicationCommandData().Options[0].Options
 
-	var err error
+	// These subcommands do not take a guide option.
+	var handle func(context.Context, *discordgo.Session, *discordgo.InteractionCreate,
+		[]*discordgo.ApplicationCommandInteractionDataOption) error
+
+	switch subcommand {
+	case guideSubCommand:
+		handle = h.handleGuide
+	case challengeSubCommandGroup:
+		handle = h.handleChallenge
+	case quizboardSubCommand:
+		handle = h.handleQuizboard
+	}
+
+	if handle != nil {
+		if err := handle(ctx, s, i, opts); err != nil {
+			if err := respondToChannel(s, i, "There was a problem processing your request", true); err != nil {
+				return errors.Wrap(err, "could not respond with processing error")
+			}
+
+			return errors.Wrap(err, "unhandled error occurred")
+		}
+
+		return nil
+	}
+
+	if opt, ok := optionsByName(opts)[guideOption]; ok && h.GuideRepo.Guide(opt.StringValue()) == nil {
+		return respondToChannel(s, i, fmt.Sprintf("Style guide %s not found", opt.StringValue()), true)
+	}
+
+	guide, err := h.guideName(ctx, i.GuildID, opts)
+	if err != nil {
+		if err := respondToChannel(s, i, "There was a problem processing your request", true); err != nil {
+			return errors.Wrap(err, "could not respond with processing error")
+		}
+
+		return errors.Wrap(err, "could not resolve style guide")
+	}
+
+	repo := h.GuideRepo.Guide(guide)
 
 	switch subcommand {
 	case randomSubCommand:
-		err = h.handleRandom(ctx, s, i, user)
+		err = h.handleRandom(ctx, s, i, repo, user, opts)
 	case infoSubCommand:
-		err = h.handleInfo(ctx, s, i, user, opts)
+		err = h.handleInfo(ctx, s, i, guide, repo, opts)
+	case searchSubCommand:
+		err = h.handleSearch(ctx, s, i, repo, opts)
+	case findSubCommand:
+		err = h.handleFind(ctx, s, i, repo, opts)
+	case compareSubCommand:
+		err = h.handleCompare(ctx, s, i, repo, opts)
+	case similarSubCommand:
+		err = h.handleSimilar(ctx, s, i, repo, opts)
+	case suggestSubCommand:
+		err = h.handleSuggest(ctx, s, i, repo, user)
+	case checkSubCommand:
+		err = h.handleCheck(ctx, s, i, repo, opts)
+	case categoriesSubCommand:
+		err = h.handleCategories(ctx, s, i, repo)
+	case tagsSubCommand:
+		err = h.handleTags(ctx, s, i, repo, opts)
+	case quizSubCommand:
+		err = h.handleQuiz(ctx, s, i, guide, repo)
 	}
 
 	if err != nil {
@@ -74,16 +392,70 @@ func (h *StylesHandler) StyleHandler(s *discordgo.Session, i *discordgo.Interact
 }
 
 func (h *StylesHandler) handleRandom(ctx context.Context, s *discordgo.Session,
-	i *discordgo.InteractionCreate, user *discordgo.User,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, user *discordgo.User,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
 ) error {
-	style := h.StyleRepo.Random(ctx)
+	options := optionsByName(opts)
+	filter := styles.RandomFilter{}
+
+	if opt, ok := options["category"]; ok {
+		filter.Category = opt.StringValue()
+	}
+
+	if opt, ok := options["tags"]; ok {
+		filter.Tags = strings.Split(opt.StringValue(), ",")
+	}
+
+	if opt, ok := options["max_abv"]; ok {
+		maxABV, err := strconv.ParseFloat(opt.StringValue(), 64)
+		if err != nil {
+			if err := respondToChannel(s, i, fmt.Sprintf("Invalid ABV: %s", opt.Value), true); err != nil {
+				return errors.Wrap(err, "could not respond with invalid ABV error")
+			}
+
+			return nil
+		}
+
+		filter.MaxABV = &maxABV
+	}
+
+	if opt, ok := options["exclude_specialty"]; ok {
+		filter.ExcludeSpecialty = opt.BoolValue()
+	}
+
+	if opt, ok := options["exclude_brewed"]; ok && opt.BoolValue() {
+		brewed, err := brewedStyles(ctx, h.BrewRepo, repo, user.ID)
+		if err != nil {
+			return errors.Wrapf(err, "could not get brewed styles for user %s", user.ID)
+		}
+
+		for number := range brewed {
+			filter.ExcludeNumbers = append(filter.ExcludeNumbers, number)
+		}
+	}
+
+	//nolint: gosec
+	seed := rand.Int63n(maxSeed)
+	if opt, ok := options["seed"]; ok {
+		seed = opt.IntValue()
+	}
+
+	style := repo.Random(ctx, filter, seed)
+	if style == nil {
+		if err := respondToChannel(s, i, "No styles match those options", true); err != nil {
+			return errors.Wrap(err, "could not respond with no styles error")
+		}
+
+		return nil
+	}
 
 	name := user.Username
 	if i.Member.Nick != "" {
 		name = i.Member.Nick
 	}
 
-	message := fmt.Sprintf("%s should brew a %s (%s - %s).", name, style.Name, style.Number, style.Category)
+	message := fmt.Sprintf("%s should brew a %s (%s - %s). Seed: %d", name, style.Name, style.Number,
+		style.Category, seed)
 
 	if err := respondToChannel(s, i, message, false); err != nil {
 		return errors.Wrap(err, "could not respond with leaderboard")
@@ -92,12 +464,131 @@ func (h *StylesHandler) handleRandom(ctx context.Context, s *discordgo.Session,
 	return nil
 }
 
-func (h *StylesHandler) handleInfo(ctx context.Context, s *discordgo.Session,
-	i *discordgo.InteractionCreate, user *discordgo.User, opts []*discordgo.ApplicationCommandInteractionDataOption,
+// brewedStyles counts the brews the user has logged of each style in the guide, in any season, keyed by style
+// number.
+func brewedStyles(ctx context.Context, brewRepo dynamo.BrewRepo, repo styles.StyleRepo,
+	userID string,
+) (map[string]float64, error) {
+	brews, err := brewRepo.GetByUserID(ctx, userID, time.Time{}.Format(time.RFC3339))
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get brews for user %s", userID)
+	}
+
+	brewed := map[string]float64{}
+
+	for _, brew := range brews {
+		if style := matchStyle(ctx, repo, brew.Style); style != nil {
+			brewed[style.Number]++
+		}
+	}
+
+	return brewed, nil
+}
+
+// matchStyle resolves the free text style of a brew to a style in the guide, either by its number or its name.
+func matchStyle(ctx context.Context, repo styles.StyleRepo, text string) *styles.Style {
+	text = strings.TrimSpace(text)
+
+	if style := repo.Get(ctx, strings.ToUpper(text)); style != nil {
+		return style
+	}
+
+	results := repo.Search(ctx, text)
+	if len(results) > 0 && strings.EqualFold(results[0].Name, text) {
+		return &results[0]
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleSearch(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	query := optionsByName(opts)["query"].StringValue()
+
+	results := repo.Search(ctx, query)
+	if len(results) == 0 {
+		if err := respondToChannel(s, i, fmt.Sprintf("No styles found for %s", query), true); err != nil {
+			return errors.Wrap(err, "could not respond with no results error")
+		}
+
+		return nil
+	}
+
+	if len(results) > maxSearchResults {
+		results = results[:maxSearchResults]
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Styles matching **%s**:\n", query))
+
+	for _, style := range results {
+		builder.WriteString(fmt.Sprintf("**%s** %s (%s)\n", style.Number, style.Name, style.Category))
+	}
+
+	if err := respondToChannel(s, i, builder.String(), true); err != nil {
+		return errors.Wrap(err, "could not respond with search results")
+	}
+
+	return nil
+}
+
+// StyleAutocompleteHandler suggests styles for the focused style number option, tags for the focused tag option, or
+// the loaded guides for the focused guide option, as the user types.
+func (h *StylesHandler) StyleAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx := context.Background()
+
+	opts := i.ApplicationCommandData().Options[0].Options
+	if i.ApplicationCommandData().Options[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+		opts = opts[0].Options
+	}
+
+	focused := focusedOption(opts)
+	if focused == nil {
+		return nil
+	}
+
+	if focused.Name == guideOption || i.ApplicationCommandData().Options[0].Name == guideSubCommand {
+		return respondWithGuideChoices(s, i, h.GuideRepo.Names(), focused.StringValue())
+	}
+
+	repo, err := h.guide(ctx, i.GuildID, opts)
+	if err != nil {
+		return errors.Wrap(err, "could not resolve style guide")
+	}
+
+	if focused.Name == "tag" {
+		return respondWithTagChoices(ctx, s, i, repo, focused.StringValue())
+	}
+
+	results := repo.Search(ctx, focused.StringValue())
+	if len(results) > maxChoices {
+		results = results[:maxChoices]
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(results))
+
+	for _, style := range results {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s - %s", style.Number, style.Name),
+			Value: style.Number,
+		})
+	}
+
+	if err := respondWithChoices(s, i, choices); err != nil {
+		return errors.Wrap(err, "could not respond with style choices")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleSimilar(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, opts []*discordgo.ApplicationCommandInteractionDataOption,
 ) error {
-	number := opts[0].StringValue()
+	number := optionsByName(opts)["number"].StringValue()
 
-	style := h.StyleRepo.Get(ctx, strings.ToUpper(number))
+	style := repo.Get(ctx, strings.ToUpper(number))
 	if style == nil {
 		if err := respondToChannel(s, i, fmt.Sprintf("Style %s not found", number), true); err != nil {
 			return errors.Wrap(err, "could not respond with not found error")
@@ -106,25 +597,539 @@ func (h *StylesHandler) handleInfo(ctx context.Context, s *discordgo.Session,
 		return nil
 	}
 
+	results := repo.Similar(ctx, style.Number)
+	if len(results) > maxSimilar {
+		results = results[:maxSimilar]
+	}
+
 	var builder strings.Builder
 
-	builder.WriteString(fmt.Sprintf("**%s** (%s)\n", style.Name, style.Number))
-	builder.WriteString(fmt.Sprintf("**Category:** %s (%s)\n", style.Category, style.CategoryNumber))
-	builder.WriteString("__**Overall Impression:**__\n")
-	builder.WriteString(fmt.Sprintf("%s\n", style.OverallImpression))
-	builder.WriteString("__**Aroma:**__\n")
-	builder.WriteString(fmt.Sprintf("%s\n", style.Aroma))
-	builder.WriteString("__**Appearance:**__\n")
-	builder.WriteString(fmt.Sprintf("%s\n", style.Appearance))
-	builder.WriteString("__**Flavor:**__\n")
-	builder.WriteString(fmt.Sprintf("%s\n", style.Flavor))
-	builder.WriteString("__**Mouthfeel:**__\n")
-	builder.WriteString(fmt.Sprintf("%s\n", style.Mouthfeel))
-	builder.WriteString("__**Examples:**__\n")
-	builder.WriteString(fmt.Sprintf("%s\n", style.CommercialExamples))
+	builder.WriteString(fmt.Sprintf("Styles most similar to **%s** (%s):\n", style.Name, style.Number))
+
+	for _, result := range results {
+		builder.WriteString(fmt.Sprintf("**%s** %s - %.0f%%\n", result.Style.Number, result.Style.Name,
+			result.Score*100)) //nolint: gomnd
+	}
 
 	if err := respondToChannel(s, i, builder.String(), true); err != nil {
-		return errors.Wrap(err, "could not respond with leaderboard")
+		return errors.Wrap(err, "could not respond with similar styles")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleSuggest(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, user *discordgo.User,
+) error {
+	brewed, err := brewedStyles(ctx, h.BrewRepo, repo, user.ID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get brewed styles for user %s", user.ID)
+	}
+
+	suggestions := repo.Suggest(ctx, brewed)
+	if len(suggestions) == 0 {
+		message := "Log some brews with a style number or name first so there is something to go on"
+		if err := respondToChannel(s, i, message, true); err != nil {
+			return errors.Wrap(err, "could not respond with no suggestions error")
+		}
+
+		return nil
+	}
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("Styles you have not brewed yet that you might like:\n")
+
+	for _, suggestion := range suggestions {
+		builder.WriteString(fmt.Sprintf("**%s** %s - close to your %s %s\n", suggestion.Style.Number,
+			suggestion.Style.Name, suggestion.Because.Number, suggestion.Because.Name))
+	}
+
+	if err := respondToChannel(s, i, builder.String(), true); err != nil {
+		return errors.Wrap(err, "could not respond with suggestions")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleCheck(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	options := optionsByName(opts)
+	number := options["number"].StringValue()
+
+	style := repo.Get(ctx, strings.ToUpper(number))
+	if style == nil {
+		if err := respondToChannel(s, i, fmt.Sprintf("Style %s not found", number), true); err != nil {
+			return errors.Wrap(err, "could not respond with not found error")
+		}
+
+		return nil
+	}
+
+	values := make(map[string]float64, len(vitals))
+
+	for _, vital := range vitals {
+		opt, ok := options[strings.ToLower(vital.name)]
+		if !ok {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(opt.StringValue(), 64)
+		if err != nil {
+			message := fmt.Sprintf("Invalid %s: %s", vital.name, opt.StringValue())
+			if err := respondToChannel(s, i, message, true); err != nil {
+				return errors.Wrap(err, "could not respond with invalid vital error")
+			}
+
+			return nil
+		}
+
+		values[vital.name] = v
+	}
+
+	if _, ok := values["ABV"]; !ok {
+		values["ABV"] = styles.EstimateABV(values["OG"], values["FG"])
+	}
+
+	_, hasIBU := values["IBU"]
+	_, hasSRM := values["SRM"]
+
+	recipe := styles.Recipe{
+		IBU:   values["IBU"],
+		OG:    values["OG"],
+		FG:    values["FG"],
+		ABV:   values["ABV"],
+		SRM:   values["SRM"],
+		NoIBU: !hasIBU,
+		NoSRM: !hasSRM,
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("Recipe vs **%s** (%s):\n", style.Name, style.Number))
+
+	for _, vital := range vitals {
+		value, ok := values[vital.name]
+		if !ok {
+			continue
+		}
+
+		builder.WriteString(checkLine(vital.name, vital.precision, vitalRange(style, vital.name), value))
+	}
+
+	builder.WriteString(betterFits(style, recipe, repo.Fits(ctx, recipe)))
+
+	if err := respondToChannel(s, i, builder.String(), true); err != nil {
+		return errors.Wrap(err, "could not respond with check results")
+	}
+
+	return nil
+}
+
+// checkLine describes whether a recipe's value for a vital is within, below or above the style's range.
+func checkLine(name string, precision int, r styles.Range, value float64) string {
+	deviation := r.Deviation(value)
+
+	switch {
+	case !r.Applicable:
+		return fmt.Sprintf("➖ %s %.*f (no range for this style)\n", name, precision, value)
+	case deviation < 0:
+		return fmt.Sprintf("⬇️ %s %.*f is %.*f below %s\n", name, precision, value, precision, -deviation,
+			formatRange(r, precision))
+	case deviation > 0:
+		return fmt.Sprintf("⬆️ %s %.*f is %.*f above %s\n", name, precision, value, precision, deviation,
+			formatRange(r, precision))
+	}
+
+	return fmt.Sprintf("✅ %s %.*f is within %s\n", name, precision, value, formatRange(r, precision))
+}
+
+// betterFits lists the styles the recipe is closer to than the checked style or, when the recipe is within every
+// range of the checked style, the other styles it is also within.
+func betterFits(style *styles.Style, recipe styles.Recipe, fits []styles.Fit) string {
+	distance := style.Distance(recipe)
+	names := []string{}
+
+	for _, fit := range fits {
+		if len(names) == maxFits {
+			break
+		}
+
+		if fit.Style.Number == style.Number {
+			continue
+		}
+
+		if fit.Distance < distance || distance == 0 && fit.Distance == 0 {
+			names = append(names, fmt.Sprintf("%s %s", fit.Style.Number, fit.Style.Name))
+		}
+	}
+
+	switch {
+	case len(names) == 0:
+		return ""
+	case distance == 0:
+		return fmt.Sprintf("Also fits: %s\n", strings.Join(names, ", "))
+	}
+
+	return fmt.Sprintf("Fits better: %s\n", strings.Join(names, ", "))
+}
+
+func (h *StylesHandler) handleCategories(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo,
+) error {
+	var builder strings.Builder
+
+	for _, category := range repo.Categories(ctx) {
+		names := make([]string, 0, len(category.Styles))
+		for _, style := range category.Styles {
+			names = append(names, fmt.Sprintf("%s %s", style.Number, style.Name))
+		}
+
+		builder.WriteString(fmt.Sprintf("**%s %s**: %s\n", category.Number, category.Name, strings.Join(names, ", ")))
+	}
+
+	if err := respondWithChunks(s, i, builder.String(), true); err != nil {
+		return errors.Wrap(err, "could not respond with categories")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleTags(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	tag := optionsByName(opts)["tag"].StringValue()
+
+	results := repo.ByTag(ctx, tag)
+	if len(results) == 0 {
+		if err := respondToChannel(s, i, fmt.Sprintf("No styles are tagged %s", tag), true); err != nil {
+			return errors.Wrap(err, "could not respond with no results error")
+		}
+
+		return nil
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("%d styles tagged **%s**:\n", len(results), tag))
+
+	for _, style := range results {
+		builder.WriteString(fmt.Sprintf("**%s** %s (%s)\n", style.Number, style.Name, style.Category))
+	}
+
+	if err := respondWithChunks(s, i, builder.String(), true); err != nil {
+		return errors.Wrap(err, "could not respond with tagged styles")
+	}
+
+	return nil
+}
+
+// respondWithTagChoices suggests the tags of the guide containing the text typed so far.
+func respondWithTagChoices(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	repo styles.StyleRepo, text string,
+) error {
+	text = strings.ToLower(strings.TrimSpace(text))
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, maxChoices)
+
+	for _, tag := range repo.Tags(ctx) {
+		if len(choices) == maxChoices {
+			break
+		}
+
+		if strings.Contains(tag, text) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  tag,
+				Value: tag,
+			})
+		}
+	}
+
+	if err := respondWithChoices(s, i, choices); err != nil {
+		return errors.Wrap(err, "could not respond with tag choices")
+	}
+
+	return nil
+}
+
+// respondWithGuideChoices suggests the loaded guides whose names contain the text typed so far.
+func respondWithGuideChoices(s *discordgo.Session, i *discordgo.InteractionCreate, guides []string, text string) error {
+	text = strings.ToLower(strings.TrimSpace(text))
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, maxChoices)
+
+	for _, guide := range guides {
+		if len(choices) == maxChoices {
+			break
+		}
+
+		if strings.Contains(strings.ToLower(guide), text) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  guide,
+				Value: guide,
+			})
+		}
+	}
+
+	if err := respondWithChoices(s, i, choices); err != nil {
+		return errors.Wrap(err, "could not respond with guide choices")
+	}
+
+	return nil
+}
+
+func (h *StylesHandler) handleFind(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	options := optionsByName(opts)
+	ranges := make(map[string]*styles.Range, len(vitals))
+
+	for _, vital := range vitals {
+		r, err := rangeOption(options, strings.ToLower(vital.name))
+		if err != nil {
+			if err := respondToChannel(s, i, err.Error(), true); err != nil {
+				return errors.Wrap(err, "could not respond with invalid range error")
+			}
+
+			return nil
+		}
+
+		ranges[vital.name] = r
+	}
+
+	results := repo.Find(ctx, styles.VitalsFilter{
+		IBU: ranges["IBU"],
+		OG:  ranges["OG"],
+		FG:  ranges["FG"],
+		ABV: ranges["ABV"],
+		SRM: ranges["SRM"],
+	})
+
+	if len(results) == 0 {
+		if err := respondToChannel(s, i, "No styles match those vitals", true); err != nil {
+			return errors.Wrap(err, "could not respond with no results error")
+		}
+
+		return nil
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("%d styles match:\n", len(results)))
+
+	for n, style := range results {
+		if n == maxFindResults {
+			builder.WriteString(fmt.Sprintf("...and %d more\n", len(results)-maxFindResults))
+
+			break
+		}
+
+		builder.WriteString(fmt.Sprintf("**%s** %s - ABV %s, IBU %s, SRM %s\n", style.Number, style.Name,
+			formatRange(style.ABV, 1), formatRange(style.IBU, 0), formatRange(style.SRM, 0)))
+	}
+
+	if err := respondToChannel(s, i, builder.String(), true); err != nil {
+		return errors.Wrap(err, "could not respond with find results")
+	}
+
+	return nil
+}
+
+// rangeOption builds a range from the optional min_ and max_ options for a vital, leaving missing bounds open.
+// Nil is returned when neither bound was given.
+func rangeOption(options map[string]*discordgo.ApplicationCommandInteractionDataOption,
+	vital string,
+) (*styles.Range, error) {
+	minOpt, hasMin := options["min_"+vital]
+	maxOpt, hasMax := options["max_"+vital]
+
+	if !hasMin && !hasMax {
+		return nil, nil
+	}
+
+	r := &styles.Range{Min: math.Inf(-1), Max: math.Inf(1), Applicable: true}
+
+	if hasMin {
+		v, err := strconv.ParseFloat(minOpt.StringValue(), 64)
+		if err != nil {
+			return nil, errors.Errorf("Invalid minimum %s: %s", vital, minOpt.StringValue())
+		}
+
+		r.Min = v
+	}
+
+	if hasMax {
+		v, err := strconv.ParseFloat(maxOpt.StringValue(), 64)
+		if err != nil {
+			return nil, errors.Errorf("Invalid maximum %s: %s", vital, maxOpt.StringValue())
+		}
+
+		r.Max = v
+	}
+
+	return r, nil
+}
+
+func formatRange(r styles.Range, precision int) string {
+	if !r.Applicable {
+		return "N/A"
+	}
+
+	return fmt.Sprintf("%.*f-%.*f", precision, r.Min, precision, r.Max)
+}
+
+func (h *StylesHandler) handleCompare(ctx context.Context, s *discordgo.Session,
+	i *discordgo.InteractionCreate, repo styles.StyleRepo, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	options := optionsByName(opts)
+	numbers := []string{options["a"].StringValue(), options["b"].StringValue()}
+	compared := make([]*styles.Style, 0, len(numbers))
+
+	for _, number := range numbers {
+		style := repo.Get(ctx, strings.ToUpper(number))
+		if style == nil {
+			if err := respondToChannel(s, i, fmt.Sprintf("Style %s not found", number), true); err != nil {
+				return errors.Wrap(err, "could not respond with not found error")
+			}
+
+			return nil
+		}
+
+		compared = append(compared, style)
+	}
+
+	a, b := compared[0], compared[1]
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s (%s) vs %s (%s)", a.Name, a.Number, b.Name, b.Number),
+	}
+
+	for _, vital := range vitals {
+		ra, rb := vitalRange(a, vital.name), vitalRange(b, vital.name)
+
+		// A vital that does not apply to either style, as with specialty styles and meads, cannot be compared.
+		var overlap string
+
+		switch intersection := ra.Intersection(rb); {
+		case !ra.Applicable || !rb.Applicable:
+			overlap = "➖ Not compared"
+		case intersection.Applicable:
+			overlap = "✅ Overlap " + formatRange(intersection, vital.precision)
+		default:
+			overlap = "❌ No overlap"
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: vital.name,
+			Value: fmt.Sprintf("%s: %s\n%s: %s\n%s", a.Number, formatRange(ra, vital.precision), b.Number,
+				formatRange(rb, vital.precision), overlap),
+			Inline: true,
+		})
+	}
+
+	for _, style := range compared {
+		if style.StyleComparison == "" {
+			continue
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s Style Comparison", style.Name),
+			Value: truncate(style.StyleComparison, maxFieldLength),
+		})
+	}
+
+	if err := respondWithEmbed(s, i, embed, false); err != nil {
+		return errors.Wrap(err, "could not respond with comparison")
+	}
+
+	return nil
+}
+
+func vitalRange(style *styles.Style, name string) styles.Range {
+	switch name {
+	case "ABV":
+		return style.ABV
+	case "IBU":
+		return style.IBU
+	case "SRM":
+		return style.SRM
+	case "OG":
+		return style.OG
+	case "FG":
+		return style.FG
+	}
+
+	return styles.Range{}
+}
+
+// truncate shortens text to at most n runes, marking where it was cut with an ellipsis.
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+
+	return string(runes[:n-1]) + "…"
+}
+
+func (h *StylesHandler) handleGuide(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	opt, ok := optionsByName(opts)["name"]
+	if !ok {
+		name, err := h.defaultGuide(ctx, i.GuildID)
+		if err != nil {
+			return errors.Wrapf(err, "could not get default guide for guild %s", i.GuildID)
+		}
+
+		message := fmt.Sprintf("The default style guide is %s. Available guides: %s", name,
+			strings.Join(h.GuideRepo.Names(), ", "))
+
+		if err := respondToChannel(s, i, message, true); err != nil {
+			return errors.Wrap(err, "could not respond with default guide")
+		}
+
+		return nil
+	}
+
+	if !isAdmin(i) {
+		if err := respondToChannel(s, i, "Only server managers can set the default style guide", true); err != nil {
+			return errors.Wrap(err, "could not respond with permission error")
+		}
+
+		return nil
+	}
+
+	name := opt.StringValue()
+
+	if h.GuideRepo.Guide(name) == nil {
+		if err := respondToChannel(s, i, fmt.Sprintf("Style guide %s not found", name), true); err != nil {
+			return errors.Wrap(err, "could not respond with not found error")
+		}
+
+		return nil
+	}
+
+	settings, err := h.GuildSettingsRepo.Get(ctx, i.GuildID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get settings for guild %s", i.GuildID)
+	}
+
+	if settings == nil {
+		settings = &dynamo.GuildSettings{GuildID: i.GuildID}
+	}
+
+	settings.StyleGuide = name
+
+	if err := h.GuildSettingsRepo.Save(ctx, settings); err != nil {
+		return errors.Wrapf(err, "could not save settings for guild %s", i.GuildID)
+	}
+
+	if err := respondToChannel(s, i, fmt.Sprintf("The default style guide is now %s", name), true); err != nil {
+		return errors.Wrap(err, "could not respond with default guide")
 	}
 
 	return nil
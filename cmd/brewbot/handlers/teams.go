@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const (
+	teamSubCommandGroup  = "team"
+	createTeamSubCommand = "create"
+	joinTeamSubCommand   = "join"
+	leaveTeamSubCommand  = "leave"
+	renameTeamSubCommand = "rename"
+	membersScope         = "members"
+	teamsScope           = "teams"
+)
+
+func teamSubCommandGroupOption() *discordgo.ApplicationCommandOption {
+	nameOption := func(description string) []*discordgo.ApplicationCommandOption {
+		return []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: description,
+				Required:    true,
+			},
+		}
+	}
+
+	return &discordgo.ApplicationCommandOption{
+		Name:        teamSubCommandGroup,
+		Description: "Manage your brewing team",
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        createTeamSubCommand,
+				Description: "Create a team and join it",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     nameOption("Name of the team"),
+			},
+			{
+				Name:        joinTeamSubCommand,
+				Description: "Join an existing team",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     nameOption("Name of the team"),
+			},
+			{
+				Name:        leaveTeamSubCommand,
+				Description: "Leave your team",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        renameTeamSubCommand,
+				Description: "Rename your team",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     nameOption("New name of the team"),
+			},
+		},
+	}
+}
+
+func (h *BrewsHandler) handleTeam(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	user *discordgo.User, opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	teams, err := h.TeamRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get teams")
+	}
+
+	subcommand := opts[0].Name
+	current := teamByMember(teams, user.ID)
+
+	var name string
+	if len(opts[0].Options) > 0 {
+		name = strings.TrimSpace(opts[0].Options[0].StringValue())
+	}
+
+	var message string
+
+	switch subcommand {
+	case createTeamSubCommand:
+		message, err = h.createTeam(ctx, teams, current, user, name)
+	case joinTeamSubCommand:
+		message, err = h.joinTeam(ctx, teams, current, user, name)
+	case leaveTeamSubCommand:
+		message, err = h.leaveTeam(ctx, current, user)
+	case renameTeamSubCommand:
+		message, err = h.renameTeam(ctx, teams, current, name)
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "could not %s team", subcommand)
+	}
+
+	if err := respondToChannel(s, i, message, true); err != nil {
+		return errors.Wrap(err, "could not respond with team message")
+	}
+
+	return nil
+}
+
+func (h *BrewsHandler) createTeam(ctx context.Context, teams []dynamo.Team, current *dynamo.Team,
+	user *discordgo.User, name string,
+) (string, error) {
+	if current != nil {
+		return fmt.Sprintf("You are already on team %s", current.Name), nil
+	}
+
+	if teamByName(teams, name) != nil {
+		return fmt.Sprintf("Team %s already exists", name), nil
+	}
+
+	team := &dynamo.Team{
+		Name:      name,
+		MemberIDs: []string{user.ID},
+	}
+
+	if err := h.TeamRepo.Save(ctx, team); err != nil {
+		return "", errors.Wrapf(err, "could not save team %s", name)
+	}
+
+	return fmt.Sprintf("Created team %s", name), nil
+}
+
+func (h *BrewsHandler) joinTeam(ctx context.Context, teams []dynamo.Team, current *dynamo.Team,
+	user *discordgo.User, name string,
+) (string, error) {
+	if current != nil {
+		return fmt.Sprintf("You are already on team %s", current.Name), nil
+	}
+
+	team := teamByName(teams, name)
+	if team == nil {
+		return fmt.Sprintf("Team %s not found", name), nil
+	}
+
+	team.MemberIDs = append(team.MemberIDs, user.ID)
+
+	if err := h.TeamRepo.Save(ctx, team); err != nil {
+		return "", errors.Wrapf(err, "could not save team %s", team.Name)
+	}
+
+	return fmt.Sprintf("Joined team %s", team.Name), nil
+}
+
+func (h *BrewsHandler) leaveTeam(ctx context.Context, current *dynamo.Team, user *discordgo.User) (string, error) {
+	if current == nil {
+		return "You are not on a team", nil
+	}
+
+	memberIDs := make([]string, 0, len(current.MemberIDs))
+
+	for _, id := range current.MemberIDs {
+		if id != user.ID {
+			memberIDs = append(memberIDs, id)
+		}
+	}
+
+	if len(memberIDs) == 0 {
+		if err := h.TeamRepo.Delete(ctx, current.ID); err != nil {
+			return "", errors.Wrapf(err, "could not delete team %s", current.Name)
+		}
+
+		return fmt.Sprintf("Left team %s, which has been disbanded", current.Name), nil
+	}
+
+	current.MemberIDs = memberIDs
+
+	if err := h.TeamRepo.Save(ctx, current); err != nil {
+		return "", errors.Wrapf(err, "could not save team %s", current.Name)
+	}
+
+	return fmt.Sprintf("Left team %s", current.Name), nil
+}
+
+func (h *BrewsHandler) renameTeam(ctx context.Context, teams []dynamo.Team, current *dynamo.Team,
+	name string,
+) (string, error) {
+	if current == nil {
+		return "You are not on a team", nil
+	}
+
+	if existing := teamByName(teams, name); existing != nil && existing.ID != current.ID {
+		return fmt.Sprintf("Team %s already exists", name), nil
+	}
+
+	oldName := current.Name
+	current.Name = name
+
+	if err := h.TeamRepo.Save(ctx, current); err != nil {
+		return "", errors.Wrapf(err, "could not save team %s", current.Name)
+	}
+
+	return fmt.Sprintf("Renamed team %s to %s", oldName, name), nil
+}
+
+// teamStandings aggregates the member standings into ranked team standings. A brew is only ever owned by one user
+// and a user can only be on one team, so each brew is counted exactly once.
+func teamStandings(teams []dynamo.Team, standings []dynamo.Standing) []dynamo.Standing {
+	byUserID := standingsByUserID(standings)
+	entries := make([]dynamo.LeaderboardEntry, 0, len(teams))
+
+	for _, team := range teams {
+		entry := dynamo.LeaderboardEntry{
+			UserID:   team.ID,
+			Username: team.Name,
+		}
+
+		for _, memberID := range team.MemberIDs {
+			entry.Count += byUserID[memberID].Count
+			entry.Volume += byUserID[memberID].Volume
+		}
+
+		if entry.Count > 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	return rankStandings(entries)
+}
+
+func teamByMember(teams []dynamo.Team, userID string) *dynamo.Team {
+	for i := range teams {
+		for _, memberID := range teams[i].MemberIDs {
+			if memberID == userID {
+				return &teams[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+func teamByName(teams []dynamo.Team, name string) *dynamo.Team {
+	for i := range teams {
+		if strings.EqualFold(teams[i].Name, name) {
+			return &teams[i]
+		}
+	}
+
+	return nil
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/platform/discord"
 	"github.com/benjaminbartels/brewbot/internal/untappd"
 	"github.com/bwmarrin/discordgo"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -19,18 +23,27 @@ const (
 )
 
 type UntapddHandler struct {
-	venues map[string]string
+	*GuideResolver
+	BrewRepo        dynamo.BrewRepo
+	VenueRepo       dynamo.VenueRepo
+	TapListInterval time.Duration
+	ScrapeCache     *untappd.Cache
+	Bot             *discord.Bot
+	Logger          *logrus.Logger
 }
 
-func NewUntapddHandler() UntapddHandler {
-	h := UntapddHandler{
-		venues: make(map[string]string),
+func NewUntapddHandler(guideResolver *GuideResolver, brewRepo dynamo.BrewRepo, venueRepo dynamo.VenueRepo,
+	tapListInterval time.Duration, scrapeCache *untappd.Cache, bot *discord.Bot, logger *logrus.Logger,
+) UntapddHandler {
+	return UntapddHandler{
+		GuideResolver:   guideResolver,
+		BrewRepo:        brewRepo,
+		VenueRepo:       venueRepo,
+		TapListInterval: tapListInterval,
+		ScrapeCache:     scrapeCache,
+		Bot:             bot,
+		Logger:          logger,
 	}
-
-	h.venues["hbs"] = "homebrewstuff-craft-bottle-shop-and-taproom/960464"
-	h.venues["brownbeard"] = "brown-beard-brewing-company/11844307"
-
-	return h
 }
 
 func UntapddCommand() *discordgo.ApplicationCommand {
@@ -44,10 +57,16 @@ func UntapddCommand() *discordgo.ApplicationCommand {
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Options: []*discordgo.ApplicationCommandOption{
 					{
-						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "name",
-						Description: "Name of the venue",
-						Required:    true,
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "name",
+						Description:  "Name of the venue",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "refresh",
+						Description: "Fetch the latest from Untappd instead of a recent copy",
 					},
 				},
 			},
@@ -57,10 +76,16 @@ func UntapddCommand() *discordgo.ApplicationCommand {
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 				Options: []*discordgo.ApplicationCommandOption{
 					{
-						Type:        discordgo.ApplicationCommandOptionString,
-						Name:        "name",
-						Description: "Name of the venue",
-						Required:    true,
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "name",
+						Description:  "Name of the venue",
+						Required:     true,
+						Autocomplete: true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "refresh",
+						Description: "Fetch the latest from Untappd instead of a recent copy",
 					},
 				},
 			},
@@ -69,6 +94,7 @@ func UntapddCommand() *discordgo.ApplicationCommand {
 				Description: "List available Untappd venues",
 				Type:        discordgo.ApplicationCommandOptionSubCommand,
 			},
+			venueSubCommandGroupOption(),
 		},
 	}
 }
@@ -87,6 +113,8 @@ func (h *UntapddHandler) UntapddHandler(s *discordgo.Session, i *discordgo.Inter
 		err = h.handleLeaderboard(ctx, s, i, opts)
 	case listVenuesSubCommand:
 		err = h.handleListVenues(ctx, s, i)
+	case venueSubCommandGroup:
+		err = h.handleVenue(ctx, s, i, opts)
 	}
 
 	if err != nil {
@@ -100,64 +128,110 @@ func (h *UntapddHandler) UntapddHandler(s *discordgo.Session, i *discordgo.Inter
 	return nil
 }
 
-func (h *UntapddHandler) handleMenu(_ context.Context, s *discordgo.Session,
+func (h *UntapddHandler) handleMenu(ctx context.Context, s *discordgo.Session,
 	i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption,
 ) error {
-	venueName := opts[0].StringValue()
+	venue, err := h.venue(ctx, s, i, opts)
+	if err != nil {
+		return errors.Wrap(err, "could not get venue")
+	}
 
-	path, ok := h.venues[venueName]
-	if !ok {
-		if err := respondToChannel(s, i, fmt.Sprintf("Invalid venue: %s", opts[1].Value), true); err != nil {
-			return errors.Wrap(err, "could not respond with invalid amount error")
-		}
+	if venue == nil {
+		return nil
 	}
 
-	menus, _, err := untappd.Scrape(path)
+	result, err := h.ScrapeCache.Get(venue.Path, refreshOption(opts))
 	if err != nil {
 		return errors.Wrap(err, "could not get scrape Untapdd")
 	}
 
+	h.logScrapeWarnings(venue, result)
+
+	if result.NoMenus {
+		message := fmt.Sprintf("Found no menus for %s. Untappd may have changed its venue pages.", venue.Name)
+		if err := respondToChannel(s, i, message, true); err != nil {
+			return errors.Wrap(err, "could not respond with no menus error")
+		}
+
+		return nil
+	}
+
+	repo, err := h.guide(ctx, i.GuildID, opts)
+	if err != nil {
+		return errors.Wrap(err, "could not resolve style guide")
+	}
+
+	brewed, err := brewedStyles(ctx, h.BrewRepo, repo, i.Member.User.ID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get brewed styles for user %s", i.Member.User.ID)
+	}
+
 	var builder strings.Builder
 
-	for _, menu := range menus {
+	neverBrewed := []string{}
+	seen := map[string]bool{}
+
+	for _, menu := range result.Menus {
 		builder.WriteString(fmt.Sprintf("__**%s**__\n", menu.Name))
-		for _, i := range menu.Items {
+		for _, item := range menu.Items {
+			style := item.Style
+
+			if match := repo.FromUntappd(ctx, item.Style); match != nil {
+				style = fmt.Sprintf("%s · %s", item.Style, match.Number)
+
+				if brewed[match.Number] == 0 && !seen[match.Number] {
+					neverBrewed = append(neverBrewed, fmt.Sprintf("%s %s", match.Number, match.Name))
+				}
+
+				seen[match.Number] = true
+			}
+
 			builder.WriteString(fmt.Sprintf("**%s** *%s* (%s) %s ABV - %s IBU\n",
-				i.Name, i.Brewery, i.Style, i.ABV, i.IBU))
+				item.Name, item.Brewery, style, item.ABV, item.IBU))
 		}
 	}
 
-	if err := respondToChannel(s, i, builder.String(), false); err != nil {
+	if len(neverBrewed) > 0 {
+		builder.WriteString(fmt.Sprintf("\nThe %s tap list has %d styles you've never brewed: %s. "+
+			"Use /styles info to read up on them.", strings.ToUpper(venue.Name), len(neverBrewed),
+			strings.Join(neverBrewed, ", ")))
+	}
+
+	builder.WriteString("\n" + skippedNote(result) + lastUpdated(result.ScrapedAt, time.Now()))
+
+	if err := respondWithChunks(s, i, builder.String(), false); err != nil {
 		return errors.Wrap(err, "could not respond with menu")
 	}
 
 	return nil
 }
 
-func (h *UntapddHandler) handleLeaderboard(_ context.Context, s *discordgo.Session,
+func (h *UntapddHandler) handleLeaderboard(ctx context.Context, s *discordgo.Session,
 	i *discordgo.InteractionCreate, opts []*discordgo.ApplicationCommandInteractionDataOption,
 ) error {
-	venueName := opts[0].StringValue()
+	venue, err := h.venue(ctx, s, i, opts)
+	if err != nil {
+		return errors.Wrap(err, "could not get venue")
+	}
 
-	path, ok := h.venues[venueName]
-	if !ok {
-		if err := respondToChannel(s, i, fmt.Sprintf("Invalid venue: %s", opts[1].Value), true); err != nil {
-			return errors.Wrap(err, "could not respond with invalid amount error")
-		}
+	if venue == nil {
+		return nil
 	}
 
-	_, patrons, err := untappd.Scrape(path)
+	result, err := h.ScrapeCache.Get(venue.Path, refreshOption(opts))
 	if err != nil {
 		return errors.Wrap(err, "could not get scrape Untapdd")
 	}
 
+	h.logScrapeWarnings(venue, result)
+
 	var builder strings.Builder
 
 	writer := tabwriter.NewWriter(&builder, 0, 4, 2, ' ', 0)
 
 	fmt.Fprintln(writer, "\tName\tCheck-Ins")
 
-	for _, p := range patrons {
+	for _, p := range result.Patrons {
 		fmt.Fprintf(writer, "%d\t%s\t%d\t\n", p.Rank, p.Name, p.CheckIns)
 	}
 
@@ -165,9 +239,9 @@ func (h *UntapddHandler) handleLeaderboard(_ context.Context, s *discordgo.Sessi
 		return errors.Wrap(err, "could not flush to channel")
 	}
 
-	message := fmt.Sprintf("%s Top Check-ins", strings.ToUpper(venueName))
+	message := fmt.Sprintf("%s Top Check-ins", strings.ToUpper(venue.Name))
 
-	message += "```\n" + builder.String() + "```"
+	message += "```\n" + builder.String() + "```" + skippedNote(result) + lastUpdated(result.ScrapedAt, time.Now())
 
 	if err := respondToChannel(s, i, message, false); err != nil {
 		return errors.Wrap(err, "could not respond with leaderboard")
@@ -176,12 +250,25 @@ func (h *UntapddHandler) handleLeaderboard(_ context.Context, s *discordgo.Sessi
 	return nil
 }
 
-func (h *UntapddHandler) handleListVenues(_ context.Context, s *discordgo.Session,
+func (h *UntapddHandler) handleListVenues(ctx context.Context, s *discordgo.Session,
 	i *discordgo.InteractionCreate,
 ) error {
+	venues, err := h.VenueRepo.GetByGuildID(ctx, i.GuildID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get venues for guild %s", i.GuildID)
+	}
+
+	if len(venues) == 0 {
+		if err := respondToChannel(s, i, "No venues yet, add one with /untapdd venue add", true); err != nil {
+			return errors.Wrap(err, "could not respond with no venues error")
+		}
+
+		return nil
+	}
+
 	var builder strings.Builder
-	for k := range h.venues {
-		builder.WriteString(fmt.Sprintf("%s\n", k))
+	for _, venue := range venues {
+		builder.WriteString(fmt.Sprintf("%s\n", venue.Name))
 	}
 
 	if err := respondToChannel(s, i, builder.String(), false); err != nil {
@@ -190,3 +277,50 @@ func (h *UntapddHandler) handleListVenues(_ context.Context, s *discordgo.Sessio
 
 	return nil
 }
+
+// logScrapeWarnings logs the parts of a venue's page that could not be parsed, which is usually the first sign
+// that Untappd has changed its pages.
+func (h *UntapddHandler) logScrapeWarnings(venue *dynamo.Venue, result untappd.Result) {
+	for _, warning := range result.Warnings {
+		h.Logger.Warnf("skipped part of venue %s: %s", venue.Path, warning)
+	}
+
+	if result.NoMenus {
+		h.Logger.Warnf("found no menus for venue %s, the page layout may have changed", venue.Path)
+	}
+}
+
+// skippedNote tells readers that some of a venue's page was left out, so a missing beer or patron is not mistaken
+// for one that is not there.
+func skippedNote(result untappd.Result) string {
+	switch len(result.Warnings) {
+	case 0:
+		return ""
+	case 1:
+		return "*1 entry could not be read and was left out.* "
+	default:
+		return fmt.Sprintf("*%d entries could not be read and were left out.* ", len(result.Warnings))
+	}
+}
+
+func refreshOption(opts []*discordgo.ApplicationCommandInteractionDataOption) bool {
+	opt, ok := optionsByName(opts)["refresh"]
+
+	return ok && opt.BoolValue()
+}
+
+// lastUpdated describes how long ago a scrape was made, so it is clear when a cached scrape is being shown.
+func lastUpdated(scrapedAt, now time.Time) string {
+	age := now.Sub(scrapedAt)
+
+	switch {
+	case age < time.Minute:
+		return "*Last updated just now*"
+	case age < 2*time.Minute: //nolint: gomnd
+		return "*Last updated 1 minute ago*"
+	case age < time.Hour:
+		return fmt.Sprintf("*Last updated %d minutes ago*", int(age.Minutes()))
+	default:
+		return fmt.Sprintf("*Last updated %s*", scrapedAt.UTC().Format("Jan 2 15:04 MST"))
+	}
+}
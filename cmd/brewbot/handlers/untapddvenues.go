@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/untappd"
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const (
+	venueSubCommandGroup = "venue"
+	addSubCommand        = "add"
+	removeSubCommand     = "remove"
+	renameSubCommand     = "rename"
+	watchSubCommand      = "watch"
+	unwatchSubCommand    = "unwatch"
+)
+
+// SeedVenues adds the venues the bot knew before venues could be managed to the guild when no venues have been added
+// yet, so that upgrading does not leave the guild without its venues.
+func SeedVenues(ctx context.Context, venueRepo dynamo.VenueRepo, guildID string) error {
+	venues, err := venueRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get venues")
+	}
+
+	if len(venues) > 0 {
+		return nil
+	}
+
+	seeds := []dynamo.Venue{
+		{GuildID: guildID, Name: "hbs", Path: "homebrewstuff-craft-bottle-shop-and-taproom/960464"},
+		{GuildID: guildID, Name: "brownbeard", Path: "brown-beard-brewing-company/11844307"},
+	}
+
+	for n := range seeds {
+		if err := venueRepo.Save(ctx, &seeds[n]); err != nil {
+			return errors.Wrapf(err, "could not save venue %s", seeds[n].Name)
+		}
+	}
+
+	return nil
+}
+
+func venueSubCommandGroupOption() *discordgo.ApplicationCommandOption {
+	nameOption := func(description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "name",
+			Description:  description,
+			Required:     true,
+			Autocomplete: true,
+		}
+	}
+
+	return &discordgo.ApplicationCommandOption{
+		Name:        venueSubCommandGroup,
+		Description: "Manage the server's Untappd venues",
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        addSubCommand,
+				Description: "Add an Untappd venue",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Short name to refer to the venue by, e.g. hbs",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "url",
+						Description: "The venue's Untappd URL, e.g. https://untappd.com/v/brown-beard-brewing-company/11844307",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Name:        removeSubCommand,
+				Description: "Remove an Untappd venue",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     []*discordgo.ApplicationCommandOption{nameOption("Name of the venue to remove")},
+			},
+			{
+				Name:        renameSubCommand,
+				Description: "Rename an Untappd venue",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption("Name of the venue to rename"),
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "new_name",
+						Description: "New name for the venue",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Name:        watchSubCommand,
+				Description: "Announce beers going on tap and being kicked at a venue",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption("Name of the venue to watch"),
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "channel",
+						Description:  "Channel to announce tap list changes in",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+						Required:     true,
+					},
+				},
+			},
+			{
+				Name:        unwatchSubCommand,
+				Description: "Stop announcing tap list changes at a venue",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options:     []*discordgo.ApplicationCommandOption{nameOption("Name of the venue to stop watching")},
+			},
+		},
+	}
+}
+
+func (h *UntapddHandler) handleVenue(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) error {
+	if !isAdmin(i) {
+		if err := respondToChannel(s, i, "Only server managers can manage venues", true); err != nil {
+			return errors.Wrap(err, "could not respond with permission error")
+		}
+
+		return nil
+	}
+
+	subcommand := opts[0].Name
+	options := optionsByName(opts[0].Options)
+	name := venueName(options["name"].StringValue())
+
+	if subcommand == addSubCommand {
+		return h.handleAddVenue(ctx, s, i, name, options["url"].StringValue())
+	}
+
+	existing, err := h.VenueRepo.Get(ctx, i.GuildID, name)
+	if err != nil {
+		return errors.Wrapf(err, "could not get venue %s", name)
+	}
+
+	var message string
+
+	switch subcommand {
+	case removeSubCommand:
+		message, err = h.removeVenue(ctx, i.GuildID, name, existing)
+	case renameSubCommand:
+		message, err = h.renameVenue(ctx, i.GuildID, existing, venueName(options["new_name"].StringValue()))
+	case watchSubCommand:
+		message, err = h.watchVenue(ctx, existing, options["channel"].ChannelValue(nil).ID)
+	case unwatchSubCommand:
+		message, err = h.watchVenue(ctx, existing, "")
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "could not %s venue %s", subcommand, name)
+	}
+
+	if err := respondToChannel(s, i, message, true); err != nil {
+		return errors.Wrap(err, "could not respond with venue message")
+	}
+
+	return nil
+}
+
+// handleAddVenue defers its response before adding the venue, as the test scrape can take longer than Discord allows
+// for a response, and then edits the response with the outcome.
+func (h *UntapddHandler) handleAddVenue(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	name, rawURL string,
+) error {
+	if err := deferResponse(s, i, true); err != nil {
+		return errors.Wrap(err, "could not defer venue response")
+	}
+
+	message, err := h.addVenue(ctx, i.GuildID, name, rawURL)
+	if err != nil {
+		h.Logger.WithError(err).Errorf("could not add venue %s", name)
+
+		message = "There was a problem processing your request"
+	}
+
+	if err := editResponse(s, i, message); err != nil {
+		return errors.Wrap(err, "could not edit venue response")
+	}
+
+	return nil
+}
+
+// addVenue adds the venue once a test scrape of its URL has found a menu or a leaderboard, so that a typo in the URL
+// is caught when the venue is added rather than when someone asks for its menu.
+func (h *UntapddHandler) addVenue(ctx context.Context, guildID, name, rawURL string) (string, error) {
+	if name == "" {
+		return "The venue needs a name", nil
+	}
+
+	existing, err := h.VenueRepo.Get(ctx, guildID, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get venue %s", name)
+	}
+
+	if existing != nil {
+		return fmt.Sprintf("There is already a venue called %s", name), nil
+	}
+
+	path, err := untappd.VenuePath(rawURL)
+	if err != nil {
+		return fmt.Sprintf("%s is not an Untappd venue URL, it should look like "+
+			"https://untappd.com/v/brown-beard-brewing-company/11844307", rawURL), nil
+	}
+
+	result, err := h.ScrapeCache.Get(path, true)
+	if err != nil || (len(result.Menus) == 0 && len(result.Patrons) == 0) {
+		return fmt.Sprintf("Could not find a menu or leaderboard at %s", rawURL), nil
+	}
+
+	if err := h.VenueRepo.Save(ctx, &dynamo.Venue{GuildID: guildID, Name: name, Path: path}); err != nil {
+		return "", errors.Wrapf(err, "could not save venue %s", name)
+	}
+
+	return fmt.Sprintf("Added %s with %d menus", name, len(result.Menus)), nil
+}
+
+func (h *UntapddHandler) removeVenue(ctx context.Context, guildID, name string,
+	existing *dynamo.Venue,
+) (string, error) {
+	if existing == nil {
+		return fmt.Sprintf("There is no venue called %s", name), nil
+	}
+
+	if err := h.VenueRepo.Delete(ctx, guildID, name); err != nil {
+		return "", errors.Wrapf(err, "could not delete venue %s", name)
+	}
+
+	return fmt.Sprintf("Removed %s", name), nil
+}
+
+// renameVenue saves the venue under its new name before deleting the old one, as the name is part of its key.
+func (h *UntapddHandler) renameVenue(ctx context.Context, guildID string, existing *dynamo.Venue,
+	newName string,
+) (string, error) {
+	if existing == nil {
+		return "There is no venue by that name", nil
+	}
+
+	if newName == "" {
+		return "The venue needs a name", nil
+	}
+
+	clash, err := h.VenueRepo.Get(ctx, guildID, newName)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not get venue %s", newName)
+	}
+
+	if clash != nil {
+		return fmt.Sprintf("There is already a venue called %s", newName), nil
+	}
+
+	oldName := existing.Name
+	existing.Name = newName
+
+	if err := h.VenueRepo.Save(ctx, existing); err != nil {
+		return "", errors.Wrapf(err, "could not save venue %s", newName)
+	}
+
+	if err := h.VenueRepo.Delete(ctx, guildID, oldName); err != nil {
+		return "", errors.Wrapf(err, "could not delete venue %s", oldName)
+	}
+
+	return fmt.Sprintf("Renamed %s to %s", oldName, newName), nil
+}
+
+// venue looks up the venue named by the name option, responding with an error and returning nil if there is no such
+// venue.
+func (h *UntapddHandler) venue(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate,
+	opts []*discordgo.ApplicationCommandInteractionDataOption,
+) (*dynamo.Venue, error) {
+	name := venueName(optionsByName(opts)["name"].StringValue())
+
+	venue, err := h.VenueRepo.Get(ctx, i.GuildID, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get venue %s", name)
+	}
+
+	if venue == nil {
+		if err := respondToChannel(s, i, fmt.Sprintf("Invalid venue: %s", name), true); err != nil {
+			return nil, errors.Wrap(err, "could not respond with invalid venue error")
+		}
+	}
+
+	return venue, nil
+}
+
+// UntapddAutocompleteHandler suggests the names of the guild's venues that contain what has been typed so far.
+func (h *UntapddHandler) UntapddAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	ctx := context.Background()
+
+	opts := i.ApplicationCommandData().Options[0].Options
+	if i.ApplicationCommandData().Options[0].Type == discordgo.ApplicationCommandOptionSubCommandGroup {
+		opts = opts[0].Options
+	}
+
+	focused := focusedOption(opts)
+	if focused == nil {
+		return nil
+	}
+
+	venues, err := h.VenueRepo.GetByGuildID(ctx, i.GuildID)
+	if err != nil {
+		return errors.Wrapf(err, "could not get venues for guild %s", i.GuildID)
+	}
+
+	sort.Slice(venues, func(i, j int) bool { return venues[i].Name < venues[j].Name })
+
+	typed := venueName(focused.StringValue())
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(venues))
+
+	for _, venue := range venues {
+		if len(choices) == maxChoices {
+			break
+		}
+
+		if strings.Contains(venue.Name, typed) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  venue.Name,
+				Value: venue.Name,
+			})
+		}
+	}
+
+	if err := respondWithChoices(s, i, choices); err != nil {
+		return errors.Wrap(err, "could not respond with venue choices")
+	}
+
+	return nil
+}
+
+// venueName normalizes venue names so that they are looked up regardless of case.
+func venueName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benjaminbartels/brewbot/internal/dynamo"
+	"github.com/benjaminbartels/brewbot/internal/untappd"
+	"github.com/pkg/errors"
+)
+
+// watchVenue starts announcing the venue's tap list changes in the channel, or stops when channelID is empty. The
+// stored tap list is cleared either way so that the first check after watching starts afresh instead of announcing
+// every change since the venue was last watched.
+func (h *UntapddHandler) watchVenue(ctx context.Context, venue *dynamo.Venue, channelID string) (string, error) {
+	if venue == nil {
+		return "There is no venue by that name", nil
+	}
+
+	venue.WatchChannelID = channelID
+	venue.TapList = nil
+	venue.CheckedAt = ""
+
+	if err := h.VenueRepo.Save(ctx, venue); err != nil {
+		return "", errors.Wrapf(err, "could not save venue %s", venue.Name)
+	}
+
+	if channelID == "" {
+		return fmt.Sprintf("Stopped watching %s", venue.Name), nil
+	}
+
+	return fmt.Sprintf("Tap list changes at %s will be announced in <#%s>", venue.Name, channelID), nil
+}
+
+// WatchTapLists is a scheduler job that checks the tap list of every watched venue once per TapListInterval and
+// announces the beers that have gone on tap or been kicked since the last check. Checks share the scrape cache with
+// the menu command, so a tap list may be as old as the cache's TTL when it is checked. The first check of a venue only
+// records its tap list. A venue that fails to scrape, or scrapes without any menus, is skipped until the next check
+// rather than announcing every beer as kicked, and a menu that scraped with warnings keeps its beers from the last
+// check so that the items that could not be parsed are not announced as kicked.
+func (h *UntapddHandler) WatchTapLists(ctx context.Context, now time.Time) error {
+	venues, err := h.VenueRepo.GetAll(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get venues")
+	}
+
+	for i := range venues {
+		venue := &venues[i]
+
+		if venue.WatchChannelID == "" {
+			continue
+		}
+
+		if venue.CheckedAt != "" {
+			checkedAt, err := time.Parse(time.RFC3339, venue.CheckedAt)
+			if err != nil {
+				h.Logger.WithError(err).Errorf("could not parse check time %s of venue %s in guild %s",
+					venue.CheckedAt, venue.Name, venue.GuildID)
+
+				continue
+			}
+
+			if now.Sub(checkedAt) < h.TapListInterval {
+				continue
+			}
+		}
+
+		if err := h.checkTapList(ctx, venue, now); err != nil {
+			h.Logger.WithError(err).Errorf("could not check tap list of venue %s in guild %s", venue.Name,
+				venue.GuildID)
+		}
+	}
+
+	return nil
+}
+
+func (h *UntapddHandler) checkTapList(ctx context.Context, venue *dynamo.Venue, now time.Time) error {
+	result, err := h.ScrapeCache.Get(venue.Path, false)
+	if err != nil {
+		return errors.Wrap(err, "could not scrape Untappd")
+	}
+
+	h.logScrapeWarnings(venue, result)
+
+	if result.NoMenus {
+		return errors.Errorf("found no menus for venue %s", venue.Path)
+	}
+
+	tapList := tapListOf(result.Menus)
+
+	if venue.CheckedAt != "" {
+		tapList = keepWarnedMenus(venue.TapList, tapList, result.Warnings)
+		added, kicked := diffTapLists(venue.TapList, tapList)
+
+		if message := tapListChanges(venue.Name, added, kicked); message != "" {
+			for _, chunk := range splitText(message, maxMessageLength) {
+				if err := h.Bot.SendMessage(venue.WatchChannelID, chunk); err != nil {
+					return errors.Wrap(err, "could not announce tap list changes")
+				}
+			}
+		}
+	}
+
+	venue.TapList = tapList
+	venue.CheckedAt = now.Format(time.RFC3339)
+
+	if err := h.VenueRepo.Save(ctx, venue); err != nil {
+		return errors.Wrapf(err, "could not save venue %s", venue.Name)
+	}
+
+	return nil
+}
+
+// tapListOf flattens the menus into a list of beers, listing a beer that appears on several menus once.
+func tapListOf(menus []untappd.Menu) []dynamo.TapBeer {
+	tapList := []dynamo.TapBeer{}
+	seen := map[string]bool{}
+
+	for _, menu := range menus {
+		for _, item := range menu.Items {
+			beer := dynamo.TapBeer{Name: item.Name, Brewery: item.Brewery, Style: item.Style, Menu: menu.Name}
+
+			if key := tapBeerKey(beer); !seen[key] {
+				seen[key] = true
+
+				tapList = append(tapList, beer)
+			}
+		}
+	}
+
+	return tapList
+}
+
+// keepWarnedMenus replaces the beers of every menu that had warnings in current with that menu's beers in previous, as
+// a menu with items that could not be parsed cannot be told apart from one whose beers have been kicked.
+func keepWarnedMenus(previous, current []dynamo.TapBeer, warnings []untappd.Warning) []dynamo.TapBeer {
+	if len(warnings) == 0 {
+		return current
+	}
+
+	warned := map[string]bool{}
+	for _, warning := range warnings {
+		warned[warning.Section] = true
+	}
+
+	tapList := []dynamo.TapBeer{}
+	seen := map[string]bool{}
+
+	add := func(beer dynamo.TapBeer) {
+		if key := tapBeerKey(beer); !seen[key] {
+			seen[key] = true
+
+			tapList = append(tapList, beer)
+		}
+	}
+
+	for _, beer := range previous {
+		if warned[beer.Menu] {
+			add(beer)
+		}
+	}
+
+	for _, beer := range current {
+		if !warned[beer.Menu] {
+			add(beer)
+		}
+	}
+
+	return tapList
+}
+
+// diffTapLists returns the beers in current that are not in previous, and the beers in previous that are not in
+// current.
+func diffTapLists(previous, current []dynamo.TapBeer) ([]dynamo.TapBeer, []dynamo.TapBeer) {
+	difference := func(a, b []dynamo.TapBeer) []dynamo.TapBeer {
+		keys := make(map[string]bool, len(b))
+		for _, beer := range b {
+			keys[tapBeerKey(beer)] = true
+		}
+
+		result := []dynamo.TapBeer{}
+
+		for _, beer := range a {
+			if !keys[tapBeerKey(beer)] {
+				result = append(result, beer)
+			}
+		}
+
+		return result
+	}
+
+	return difference(current, previous), difference(previous, current)
+}
+
+func tapListChanges(venueName string, added, kicked []dynamo.TapBeer) string {
+	var builder strings.Builder
+
+	if len(added) > 0 {
+		builder.WriteString(fmt.Sprintf("🍺 New on tap at %s:\n", strings.ToUpper(venueName)))
+
+		for _, beer := range added {
+			builder.WriteString(fmt.Sprintf("**%s** *%s* (%s)\n", beer.Name, beer.Brewery, beer.Style))
+		}
+	}
+
+	if len(kicked) > 0 {
+		builder.WriteString(fmt.Sprintf("💀 Kicked at %s:\n", strings.ToUpper(venueName)))
+
+		for _, beer := range kicked {
+			builder.WriteString(fmt.Sprintf("**%s** *%s*\n", beer.Name, beer.Brewery))
+		}
+	}
+
+	return builder.String()
+}
+
+func tapBeerKey(beer dynamo.TapBeer) string {
+	return strings.ToLower(beer.Brewery + "\x00" + beer.Name)
+}
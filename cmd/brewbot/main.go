@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -15,7 +16,9 @@ import (
 	"github.com/benjaminbartels/brewbot/internal/dynamo"
 	c "github.com/benjaminbartels/brewbot/internal/platform/context"
 	"github.com/benjaminbartels/brewbot/internal/platform/discord"
+	"github.com/benjaminbartels/brewbot/internal/platform/scheduler"
 	"github.com/benjaminbartels/brewbot/internal/styles"
+	"github.com/benjaminbartels/brewbot/internal/untappd"
 	"github.com/bwmarrin/discordgo"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/pkg/errors"
@@ -28,14 +31,35 @@ const (
 )
 
 type config struct {
-	AWSRegion            string `default:"us-west-2"`
-	BrewTableName        string `default:"BeerBot-Brews"`
-	LeaderboardTableName string `default:"BeerBot-LeaderboardEntries"`
-	UseLocalDynamo       bool   `default:"false"`
-	DiscordToken         string `required:"true"`
-	DiscordGuildID       string `required:"true"`
-	LeaderboardCutoff    string `required:"true"`
-	Debug                bool   `default:"false"`
+	AWSRegion              string        `default:"us-west-2"`
+	BrewTableName          string        `default:"BeerBot-Brews"`
+	LeaderboardTableName   string        `default:"BeerBot-LeaderboardEntries"`
+	DigestTableName        string        `default:"BeerBot-Digests"`
+	SnapshotTableName      string        `default:"BeerBot-LeaderboardSnapshots"`
+	NotificationTableName  string        `default:"BeerBot-NotificationPreferences"`
+	TeamTableName          string        `default:"BeerBot-Teams"`
+	GuildSettingsTableName string        `default:"BeerBot-GuildSettings"`
+	ChallengeTableName     string        `default:"BeerBot-Challenges"`
+	QuizScoreTableName     string        `default:"BeerBot-QuizScores"`
+	QuizTableName          string        `default:"BeerBot-Quizzes"`
+	VenueTableName         string        `default:"BeerBot-Venues"`
+	UseLocalDynamo         bool          `default:"false"`
+	DiscordToken           string        `required:"true"`
+	DiscordGuildID         string        `required:"true"`
+	LeaderboardCutoff      string        `required:"true"`
+	SchedulerInterval      time.Duration `default:"1m"`
+	NotificationCooldown   time.Duration `default:"1h"`
+	TapListInterval        time.Duration `default:"15m"`
+	ScrapeCacheTTL         time.Duration `default:"5m"`
+	ScrapeCacheFile        string
+	UntappdBaseURL         string `default:"https://untappd.com/v/"`
+	UntappdUserAgent       string
+	UntappdTimeout         time.Duration `default:"10s"`
+	TopBrewerRoleID        string
+	MilestoneRoles         map[string]string
+	StyleGuideDir          string
+	DefaultStyleGuide      string `default:"bjcp-2021"`
+	Debug                  bool   `default:"false"`
 }
 
 func main() {
@@ -100,22 +124,71 @@ func run(logger *logrus.Logger, cfg config) error {
 
 	brewRepo := dynamo.NewBrewRepo(dynamodb.NewFromConfig(awsCfg), cfg.BrewTableName)
 	leaderboardRepo := dynamo.NewLeaderboardRepo(dynamodb.NewFromConfig(awsCfg), cfg.LeaderboardTableName)
-	stylesRepo, err := styles.NewStyleRepo("styles.json")
+	digestRepo := dynamo.NewDigestRepo(dynamodb.NewFromConfig(awsCfg), cfg.DigestTableName)
+	snapshotRepo := dynamo.NewSnapshotRepo(dynamodb.NewFromConfig(awsCfg), cfg.SnapshotTableName)
+	notificationPreferenceRepo := dynamo.NewNotificationPreferenceRepo(dynamodb.NewFromConfig(awsCfg),
+		cfg.NotificationTableName)
+	teamRepo := dynamo.NewTeamRepo(dynamodb.NewFromConfig(awsCfg), cfg.TeamTableName)
+	guildSettingsRepo := dynamo.NewGuildSettingsRepo(dynamodb.NewFromConfig(awsCfg), cfg.GuildSettingsTableName)
+	challengeRepo := dynamo.NewChallengeRepo(dynamodb.NewFromConfig(awsCfg), cfg.ChallengeTableName)
+	quizScoreRepo := dynamo.NewQuizScoreRepo(dynamodb.NewFromConfig(awsCfg), cfg.QuizScoreTableName)
+	quizRepo := dynamo.NewQuizRepo(dynamodb.NewFromConfig(awsCfg), cfg.QuizTableName)
+	venueRepo := dynamo.NewVenueRepo(dynamodb.NewFromConfig(awsCfg), cfg.VenueTableName)
+
+	if err := handlers.SeedVenues(ctx, venueRepo, cfg.DiscordGuildID); err != nil {
+		return errors.Wrap(err, "could not seed venues")
+	}
+
+	guideRepo, err := styles.NewGuideRepo(cfg.StyleGuideDir)
 	if err != nil {
-		return errors.Wrap(err, "could create new style repo")
+		return errors.Wrap(err, "could create new guide repo")
+	}
+
+	if guideRepo.Guide(cfg.DefaultStyleGuide) == nil {
+		return errors.Errorf("default style guide %s not found", cfg.DefaultStyleGuide)
+	}
+
+	if err := guideRepo.Watch(ctx, func(err error) {
+		logger.WithError(err).Error("could not reload style guides")
+	}); err != nil {
+		return errors.Wrap(err, "could not watch style guides")
 	}
 
 	bot := discord.NewBot(session, cfg.DiscordGuildID, logger)
+	sched := scheduler.New(cfg.SchedulerInterval, logger)
+
+	scraper := &untappd.Scraper{
+		BaseURL:   cfg.UntappdBaseURL,
+		UserAgent: cfg.UntappdUserAgent,
+		Timeout:   cfg.UntappdTimeout,
+	}
+
+	scrapeCache, err := untappd.NewCache(cfg.ScrapeCacheTTL, scraper.Scrape, cfg.ScrapeCacheFile)
+	if err != nil {
+		return errors.Wrap(err, "could not create scrape cache")
+	}
+
+	sched.AddJob("scrapecache", scrapeCache.Save)
 
 	cutoff, err := time.Parse(cuttoffFormat, cfg.LeaderboardCutoff)
 	if err != nil {
 		return errors.Wrapf(err, "could parse date %s", cfg.LeaderboardCutoff)
 	}
 
-	if err := handlers.NewAPI(bot, brewRepo, leaderboardRepo, stylesRepo, cutoff, logger); err != nil {
+	roleAwards, err := parseRoleAwards(cfg)
+	if err != nil {
+		return errors.Wrap(err, "could not parse role awards")
+	}
+
+	if err := handlers.NewAPI(bot, sched, brewRepo, leaderboardRepo, digestRepo, snapshotRepo,
+		notificationPreferenceRepo, teamRepo, guildSettingsRepo, challengeRepo, quizScoreRepo, quizRepo,
+		venueRepo, scrapeCache, guideRepo, cfg.DefaultStyleGuide, cutoff, cfg.NotificationCooldown,
+		cfg.TapListInterval, roleAwards, logger); err != nil {
 		return errors.Wrap(err, "could not create new API")
 	}
 
+	go sched.Run(ctx)
+
 	logger.Infof("brewbot started")
 
 	defer logger.Info("brewbot stopped 👋!")
@@ -128,3 +201,24 @@ func run(logger *logrus.Logger, cfg config) error {
 
 	return nil
 }
+
+// parseRoleAwards reads the milestone roles, configured as "gallons:roleID" pairs, into handlers.RoleAwards.
+func parseRoleAwards(cfg config) (handlers.RoleAwards, error) {
+	roleAwards := handlers.RoleAwards{
+		TopBrewerRoleID: cfg.TopBrewerRoleID,
+	}
+
+	for volume, roleID := range cfg.MilestoneRoles {
+		v, err := strconv.ParseFloat(volume, 64)
+		if err != nil {
+			return roleAwards, errors.Wrapf(err, "invalid milestone volume %s", volume)
+		}
+
+		roleAwards.Milestones = append(roleAwards.Milestones, handlers.Milestone{
+			Volume: v,
+			RoleID: roleID,
+		})
+	}
+
+	return roleAwards, nil
+}
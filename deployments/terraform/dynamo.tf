@@ -39,6 +39,129 @@ resource "aws_dynamodb_table" "leaderboard-table" {
   }
 }
 
+resource "aws_dynamodb_table" "digests-table" {
+  name           = "BeerBot-Digests"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "guildId"
+
+  attribute {
+    name = "guildId"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "snapshots-table" {
+  name           = "BeerBot-LeaderboardSnapshots"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "season"
+  range_key      = "date"
+
+  attribute {
+    name = "season"
+    type = "S"
+  }
+
+  attribute {
+    name = "date"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "notification-preferences-table" {
+  name           = "BeerBot-NotificationPreferences"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "userId"
+
+  attribute {
+    name = "userId"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "teams-table" {
+  name           = "BeerBot-Teams"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "guild-settings-table" {
+  name           = "BeerBot-GuildSettings"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "guildId"
+
+  attribute {
+    name = "guildId"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "challenges-table" {
+  name           = "BeerBot-Challenges"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "guildId"
+  range_key      = "startsAt"
+
+  attribute {
+    name = "guildId"
+    type = "S"
+  }
+
+  attribute {
+    name = "startsAt"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "quiz-scores-table" {
+  name           = "BeerBot-QuizScores"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "guildId"
+  range_key      = "userId"
+
+  attribute {
+    name = "guildId"
+    type = "S"
+  }
+
+  attribute {
+    name = "userId"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "quizzes-table" {
+  name           = "BeerBot-Quizzes"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+}
+
+resource "aws_dynamodb_table" "venues-table" {
+  name           = "BeerBot-Venues"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "guildId"
+  range_key      = "name"
+
+  attribute {
+    name = "guildId"
+    type = "S"
+  }
+
+  attribute {
+    name = "name"
+    type = "S"
+  }
+}
+
 resource "aws_iam_user" "brewbot_user" {
   name = "brewbot"
 }
@@ -63,6 +186,15 @@ data "aws_iam_policy_document" "brewbot_role_policy" {
       "${aws_dynamodb_table.brews-table.arn}/index/*",
       aws_dynamodb_table.leaderboard-table.arn,
       "${aws_dynamodb_table.leaderboard-table.arn}/index/*",
+      aws_dynamodb_table.digests-table.arn,
+      aws_dynamodb_table.snapshots-table.arn,
+      aws_dynamodb_table.notification-preferences-table.arn,
+      aws_dynamodb_table.teams-table.arn,
+      aws_dynamodb_table.guild-settings-table.arn,
+      aws_dynamodb_table.challenges-table.arn,
+      aws_dynamodb_table.quiz-scores-table.arn,
+      aws_dynamodb_table.quizzes-table.arn,
+      aws_dynamodb_table.venues-table.arn,
 
     ]
   }
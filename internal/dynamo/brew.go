@@ -26,6 +26,7 @@ type Brew struct {
 	Username  string  `dynamodbav:"username"`
 	Style     string  `dynamodbav:"style"`
 	Amount    float64 `dynamodbav:"amount"`
+	Details   string  `dynamodbav:"details"`
 	CreatedAt string  `dynamodbav:"createdAt"`
 }
 
@@ -102,6 +103,38 @@ func (r *BrewDB) GetByUserID(ctx context.Context, userID, createdAfter string) (
 	return brews, nil
 }
 
+func (r *BrewDB) GetCreatedAfter(ctx context.Context, createdAfter string) ([]Brew, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+		ScanFilter: map[string]types.Condition{
+			"createdAt": {
+				ComparisonOperator: types.ComparisonOperatorGt,
+				AttributeValueList: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: createdAfter},
+				},
+			},
+		},
+	}
+
+	scanOutput, err := r.client.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not scan brew items")
+	}
+
+	if scanOutput == nil || scanOutput.Items == nil || len(scanOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	brews := []Brew{}
+
+	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &brews)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal brew items")
+	}
+
+	return brews, nil
+}
+
 func (r *BrewDB) Save(ctx context.Context, brew *Brew) error {
 	brew.TypeName = "Brew"
 
@@ -0,0 +1,138 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ ChallengeRepo = (*ChallengeDB)(nil)
+
+type ChallengeDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// Challenge is a guild's style challenge, in which members brew a given style before it ends. The participants are
+// recorded when the challenge ends, which is when EndedAt is set.
+type Challenge struct {
+	TypeName     string        `dynamodbav:"__typename"`
+	GuildID      string        `dynamodbav:"guildId"`
+	StartsAt     string        `dynamodbav:"startsAt"`
+	EndsAt       string        `dynamodbav:"endsAt"`
+	EndedAt      string        `dynamodbav:"endedAt"`
+	ChannelID    string        `dynamodbav:"channelId"`
+	Guide        string        `dynamodbav:"guide"`
+	StyleNumber  string        `dynamodbav:"styleNumber"`
+	StyleName    string        `dynamodbav:"styleName"`
+	Participants []Participant `dynamodbav:"participants"`
+	UpdatedAt    string        `dynamodbav:"updatedAt"`
+}
+
+// Participant is a user who brewed the style of a challenge, along with how much they brewed.
+type Participant struct {
+	UserID   string  `dynamodbav:"userId"`
+	Username string  `dynamodbav:"username"`
+	Count    int     `dynamodbav:"count"`
+	Volume   float64 `dynamodbav:"volume"`
+}
+
+func NewChallengeRepo(client *dynamodb.Client, tableName string) *ChallengeDB {
+	return &ChallengeDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetByGuildID returns every challenge of the guild, newest first.
+func (r *ChallengeDB) GetByGuildID(ctx context.Context, guildID string) ([]Challenge, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName: aws.String(r.tableName),
+		KeyConditions: map[string]types.Condition{
+			"guildId": {
+				ComparisonOperator: types.ComparisonOperatorEq,
+				AttributeValueList: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: guildID},
+				},
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	queryOutput, err := r.client.Query(ctx, queryInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query challenge items")
+	}
+
+	if queryOutput == nil || queryOutput.Items == nil || len(queryOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	challenges := []Challenge{}
+
+	err = attributevalue.UnmarshalListOfMaps(queryOutput.Items, &challenges)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal challenge items")
+	}
+
+	return challenges, nil
+}
+
+func (r *ChallengeDB) GetAll(ctx context.Context) ([]Challenge, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	scanOutput, err := r.client.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not scan challenge items")
+	}
+
+	if scanOutput == nil || scanOutput.Items == nil || len(scanOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	challenges := []Challenge{}
+
+	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &challenges)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal challenge items")
+	}
+
+	return challenges, nil
+}
+
+func (r *ChallengeDB) Save(ctx context.Context, challenge *Challenge) error {
+	challenge.TypeName = "Challenge"
+
+	if challenge.GuildID == "" {
+		return errors.New("guildId is required")
+	}
+
+	if challenge.StartsAt == "" {
+		return errors.New("startsAt is required")
+	}
+
+	challenge.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	avMap, err := attributevalue.MarshalMap(challenge)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal challenge item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put challenge item")
+	}
+
+	return nil
+}
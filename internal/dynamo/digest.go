@@ -0,0 +1,144 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ DigestRepo = (*DigestDB)(nil)
+
+type DigestDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// Digest is a guild's leaderboard digest configuration along with the standings that were posted in the previous
+// digest, which are used to show movement.
+type Digest struct {
+	TypeName      string     `dynamodbav:"__typename"`
+	GuildID       string     `dynamodbav:"guildId"`
+	ChannelID     string     `dynamodbav:"channelId"`
+	Schedule      string     `dynamodbav:"schedule"`
+	ScheduledAt   string     `dynamodbav:"scheduledAt"`
+	LastPostedAt  string     `dynamodbav:"lastPostedAt"`
+	LastStandings []Standing `dynamodbav:"lastStandings"`
+	UpdatedAt     string     `dynamodbav:"updatedAt"`
+}
+
+// Standing is a user's position on the leaderboard at a point in time.
+type Standing struct {
+	UserID   string  `dynamodbav:"userId"`
+	Username string  `dynamodbav:"username"`
+	Rank     int     `dynamodbav:"rank"`
+	Count    int     `dynamodbav:"count"`
+	Volume   float64 `dynamodbav:"volume"`
+}
+
+func NewDigestRepo(client *dynamodb.Client, tableName string) *DigestDB {
+	return &DigestDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *DigestDB) Get(ctx context.Context, guildID string) (*Digest, error) {
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"guildId": &types.AttributeValueMemberS{Value: guildID},
+		},
+	}
+
+	getItemOutput, err := r.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get digest item")
+	}
+
+	if getItemOutput.Item == nil || len(getItemOutput.Item) == 0 {
+		return nil, nil
+	}
+
+	digest := &Digest{}
+
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, digest)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal digest item")
+	}
+
+	return digest, nil
+}
+
+func (r *DigestDB) GetAll(ctx context.Context) ([]Digest, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	scanOutput, err := r.client.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not scan digest items")
+	}
+
+	if scanOutput == nil || scanOutput.Items == nil || len(scanOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	digests := []Digest{}
+
+	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &digests)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal digest items")
+	}
+
+	return digests, nil
+}
+
+func (r *DigestDB) Save(ctx context.Context, digest *Digest) error {
+	digest.TypeName = "Digest"
+
+	if digest.GuildID == "" {
+		return errors.New("guildId is required")
+	}
+
+	if digest.ChannelID == "" {
+		return errors.New("channelId is required")
+	}
+
+	digest.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	avMap, err := attributevalue.MarshalMap(digest)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal digest item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put digest item")
+	}
+
+	return nil
+}
+
+func (r *DigestDB) Delete(ctx context.Context, guildID string) error {
+	deleteItemInput := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"guildId": &types.AttributeValueMemberS{Value: guildID},
+		},
+	}
+
+	if _, err := r.client.DeleteItem(ctx, deleteItemInput); err != nil {
+		return errors.Wrap(err, "could delete digest item")
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ GuildSettingsRepo = (*GuildSettingsDB)(nil)
+
+type GuildSettingsDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// GuildSettings holds a guild's preferences. When AutoChallenge is set, a new style challenge is started in
+// ChallengeChannelID whenever there is none running.
+type GuildSettings struct {
+	TypeName           string `dynamodbav:"__typename"`
+	GuildID            string `dynamodbav:"guildId"`
+	StyleGuide         string `dynamodbav:"styleGuide"`
+	AutoChallenge      bool   `dynamodbav:"autoChallenge"`
+	ChallengeChannelID string `dynamodbav:"challengeChannelId"`
+	UpdatedAt          string `dynamodbav:"updatedAt"`
+}
+
+func NewGuildSettingsRepo(client *dynamodb.Client, tableName string) *GuildSettingsDB {
+	return &GuildSettingsDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *GuildSettingsDB) Get(ctx context.Context, guildID string) (*GuildSettings, error) {
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"guildId": &types.AttributeValueMemberS{Value: guildID},
+		},
+	}
+
+	getItemOutput, err := r.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get guild settings item")
+	}
+
+	if getItemOutput.Item == nil || len(getItemOutput.Item) == 0 {
+		return nil, nil
+	}
+
+	settings := &GuildSettings{}
+
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, settings)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal guild settings item")
+	}
+
+	return settings, nil
+}
+
+func (r *GuildSettingsDB) GetAll(ctx context.Context) ([]GuildSettings, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	scanOutput, err := r.client.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not scan guild settings items")
+	}
+
+	if scanOutput == nil || scanOutput.Items == nil || len(scanOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	settings := []GuildSettings{}
+
+	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &settings)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal guild settings items")
+	}
+
+	return settings, nil
+}
+
+func (r *GuildSettingsDB) Save(ctx context.Context, settings *GuildSettings) error {
+	settings.TypeName = "GuildSettings"
+
+	if settings.GuildID == "" {
+		return errors.New("guildId is required")
+	}
+
+	settings.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	avMap, err := attributevalue.MarshalMap(settings)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal guild settings item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put guild settings item")
+	}
+
+	return nil
+}
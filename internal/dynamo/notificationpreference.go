@@ -0,0 +1,87 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ NotificationPreferenceRepo = (*NotificationPreferenceDB)(nil)
+
+type NotificationPreferenceDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type NotificationPreference struct {
+	TypeName       string `dynamodbav:"__typename"`
+	UserID         string `dynamodbav:"userId"`
+	Overtaken      bool   `dynamodbav:"overtaken"`
+	LastNotifiedAt string `dynamodbav:"lastNotifiedAt"`
+	UpdatedAt      string `dynamodbav:"updatedAt"`
+}
+
+func NewNotificationPreferenceRepo(client *dynamodb.Client, tableName string) *NotificationPreferenceDB {
+	return &NotificationPreferenceDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *NotificationPreferenceDB) Get(ctx context.Context, userID string) (*NotificationPreference, error) {
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"userId": &types.AttributeValueMemberS{Value: userID},
+		},
+	}
+
+	getItemOutput, err := r.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get notification preference item")
+	}
+
+	if getItemOutput.Item == nil || len(getItemOutput.Item) == 0 {
+		return nil, nil
+	}
+
+	preference := &NotificationPreference{}
+
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, preference)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal notification preference item")
+	}
+
+	return preference, nil
+}
+
+func (r *NotificationPreferenceDB) Save(ctx context.Context, preference *NotificationPreference) error {
+	preference.TypeName = "NotificationPreference"
+
+	if preference.UserID == "" {
+		return errors.New("userId is required")
+	}
+
+	preference.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	avMap, err := attributevalue.MarshalMap(preference)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal notification preference item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put notification preference item")
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ QuizRepo = (*QuizDB)(nil)
+
+type QuizDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// Quiz is a style quiz issued to a user, kept so that the answer never leaves the bot and so that the quiz can be
+// answered only once, even if the guide it came from changes in the meantime. Picked and AnsweredAt are set when the
+// user answers.
+type Quiz struct {
+	TypeName    string       `dynamodbav:"__typename"`
+	ID          string       `dynamodbav:"id"`
+	GuildID     string       `dynamodbav:"guildId"`
+	UserID      string       `dynamodbav:"userId"`
+	Guide       string       `dynamodbav:"guide"`
+	StyleNumber string       `dynamodbav:"styleNumber"`
+	StyleName   string       `dynamodbav:"styleName"`
+	Choices     []QuizChoice `dynamodbav:"choices"`
+	Answer      int          `dynamodbav:"answer"`
+	Picked      int          `dynamodbav:"picked"`
+	AnsweredAt  string       `dynamodbav:"answeredAt"`
+	CreatedAt   string       `dynamodbav:"createdAt"`
+	UpdatedAt   string       `dynamodbav:"updatedAt"`
+}
+
+// QuizChoice is a style offered as an answer to a quiz.
+type QuizChoice struct {
+	Number string `dynamodbav:"number"`
+	Name   string `dynamodbav:"name"`
+}
+
+func NewQuizRepo(client *dynamodb.Client, tableName string) *QuizDB {
+	return &QuizDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *QuizDB) Get(ctx context.Context, id string) (*Quiz, error) {
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	}
+
+	getItemOutput, err := r.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get quiz item")
+	}
+
+	if getItemOutput.Item == nil || len(getItemOutput.Item) == 0 {
+		return nil, nil
+	}
+
+	quiz := &Quiz{}
+
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, quiz)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal quiz item")
+	}
+
+	return quiz, nil
+}
+
+func (r *QuizDB) Save(ctx context.Context, quiz *Quiz) error {
+	quiz.TypeName = "Quiz"
+
+	if quiz.ID == "" {
+		return errors.New("id is required")
+	}
+
+	quiz.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if quiz.CreatedAt == "" {
+		quiz.CreatedAt = quiz.UpdatedAt
+	}
+
+	avMap, err := attributevalue.MarshalMap(quiz)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal quiz item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put quiz item")
+	}
+
+	return nil
+}
+
+// Answer records the user's pick for the quiz, but only if the quiz has not already been answered, so that two
+// clicks racing each other cannot both be scored. It returns false when the quiz had already been answered.
+func (r *QuizDB) Answer(ctx context.Context, quiz *Quiz, picked int) (bool, error) {
+	answered := *quiz
+	answered.TypeName = "Quiz"
+	answered.Picked = picked
+	answered.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	answered.AnsweredAt = answered.UpdatedAt
+
+	avMap, err := attributevalue.MarshalMap(answered)
+	if err != nil {
+		return false, errors.Wrap(err, "could not marshal quiz item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                avMap,
+		ConditionExpression: aws.String("answeredAt = :unanswered"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":unanswered": &types.AttributeValueMemberS{Value: ""},
+		},
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		var conditionErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "could put quiz item")
+	}
+
+	*quiz = answered
+
+	return true, nil
+}
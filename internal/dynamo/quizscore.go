@@ -0,0 +1,129 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ QuizScoreRepo = (*QuizScoreDB)(nil)
+
+type QuizScoreDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// QuizScore is a user's running tally of style quiz answers in a guild.
+type QuizScore struct {
+	TypeName   string `dynamodbav:"__typename"`
+	GuildID    string `dynamodbav:"guildId"`
+	UserID     string `dynamodbav:"userId"`
+	Username   string `dynamodbav:"username"`
+	Correct    int    `dynamodbav:"correct"`
+	Answered   int    `dynamodbav:"answered"`
+	Streak     int    `dynamodbav:"streak"`
+	BestStreak int    `dynamodbav:"bestStreak"`
+	UpdatedAt  string `dynamodbav:"updatedAt"`
+}
+
+func NewQuizScoreRepo(client *dynamodb.Client, tableName string) *QuizScoreDB {
+	return &QuizScoreDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *QuizScoreDB) Get(ctx context.Context, guildID, userID string) (*QuizScore, error) {
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"guildId": &types.AttributeValueMemberS{Value: guildID},
+			"userId":  &types.AttributeValueMemberS{Value: userID},
+		},
+	}
+
+	getItemOutput, err := r.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get quiz score item")
+	}
+
+	if getItemOutput.Item == nil || len(getItemOutput.Item) == 0 {
+		return nil, nil
+	}
+
+	score := &QuizScore{}
+
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, score)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal quiz score item")
+	}
+
+	return score, nil
+}
+
+func (r *QuizScoreDB) GetByGuildID(ctx context.Context, guildID string) ([]QuizScore, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName: aws.String(r.tableName),
+		KeyConditions: map[string]types.Condition{
+			"guildId": {
+				ComparisonOperator: types.ComparisonOperatorEq,
+				AttributeValueList: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: guildID},
+				},
+			},
+		},
+	}
+
+	queryOutput, err := r.client.Query(ctx, queryInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query quiz score items")
+	}
+
+	if queryOutput == nil || queryOutput.Items == nil || len(queryOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	scores := []QuizScore{}
+
+	err = attributevalue.UnmarshalListOfMaps(queryOutput.Items, &scores)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal quiz score items")
+	}
+
+	return scores, nil
+}
+
+func (r *QuizScoreDB) Save(ctx context.Context, score *QuizScore) error {
+	score.TypeName = "QuizScore"
+
+	if score.GuildID == "" {
+		return errors.New("guildId is required")
+	}
+
+	if score.UserID == "" {
+		return errors.New("userId is required")
+	}
+
+	score.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	avMap, err := attributevalue.MarshalMap(score)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal quiz score item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put quiz score item")
+	}
+
+	return nil
+}
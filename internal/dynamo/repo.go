@@ -5,6 +5,7 @@ import "context"
 type BrewRepo interface {
 	Get(ctx context.Context, id string) (*Brew, error)
 	GetByUserID(ctx context.Context, userID string, createdAfter string) ([]Brew, error)
+	GetCreatedAfter(ctx context.Context, createdAfter string) ([]Brew, error)
 	Save(ctx context.Context, brew *Brew) error
 	Delete(ctx context.Context, id string) error
 }
@@ -15,3 +16,58 @@ type LeaderboardRepo interface {
 	Save(ctx context.Context, leaderboardEntry *LeaderboardEntry) error
 	Delete(ctx context.Context, id string) error
 }
+
+type DigestRepo interface {
+	Get(ctx context.Context, guildID string) (*Digest, error)
+	GetAll(ctx context.Context) ([]Digest, error)
+	Save(ctx context.Context, digest *Digest) error
+	Delete(ctx context.Context, guildID string) error
+}
+
+type SnapshotRepo interface {
+	GetAsOf(ctx context.Context, season, date string) (*LeaderboardSnapshot, error)
+	Save(ctx context.Context, snapshot *LeaderboardSnapshot) error
+}
+
+type NotificationPreferenceRepo interface {
+	Get(ctx context.Context, userID string) (*NotificationPreference, error)
+	Save(ctx context.Context, preference *NotificationPreference) error
+}
+
+type TeamRepo interface {
+	GetAll(ctx context.Context) ([]Team, error)
+	Save(ctx context.Context, team *Team) error
+	Delete(ctx context.Context, id string) error
+}
+
+type GuildSettingsRepo interface {
+	Get(ctx context.Context, guildID string) (*GuildSettings, error)
+	GetAll(ctx context.Context) ([]GuildSettings, error)
+	Save(ctx context.Context, settings *GuildSettings) error
+}
+
+type ChallengeRepo interface {
+	GetByGuildID(ctx context.Context, guildID string) ([]Challenge, error)
+	GetAll(ctx context.Context) ([]Challenge, error)
+	Save(ctx context.Context, challenge *Challenge) error
+}
+
+type QuizScoreRepo interface {
+	Get(ctx context.Context, guildID, userID string) (*QuizScore, error)
+	GetByGuildID(ctx context.Context, guildID string) ([]QuizScore, error)
+	Save(ctx context.Context, score *QuizScore) error
+}
+
+type QuizRepo interface {
+	Get(ctx context.Context, id string) (*Quiz, error)
+	Save(ctx context.Context, quiz *Quiz) error
+	Answer(ctx context.Context, quiz *Quiz, picked int) (bool, error)
+}
+
+type VenueRepo interface {
+	Get(ctx context.Context, guildID, name string) (*Venue, error)
+	GetByGuildID(ctx context.Context, guildID string) ([]Venue, error)
+	GetAll(ctx context.Context) ([]Venue, error)
+	Save(ctx context.Context, venue *Venue) error
+	Delete(ctx context.Context, guildID, name string) error
+}
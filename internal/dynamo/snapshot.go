@@ -0,0 +1,106 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ SnapshotRepo = (*SnapshotDB)(nil)
+
+type SnapshotDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// LeaderboardSnapshot is the computed leaderboard for a season as it stood at the end of a given day.
+type LeaderboardSnapshot struct {
+	TypeName  string     `dynamodbav:"__typename"`
+	Season    string     `dynamodbav:"season"`
+	Date      string     `dynamodbav:"date"`
+	Standings []Standing `dynamodbav:"standings"`
+	UpdatedAt string     `dynamodbav:"updatedAt"`
+}
+
+func NewSnapshotRepo(client *dynamodb.Client, tableName string) *SnapshotDB {
+	return &SnapshotDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetAsOf returns the most recent snapshot for the season taken on or before date.
+func (r *SnapshotDB) GetAsOf(ctx context.Context, season, date string) (*LeaderboardSnapshot, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName: aws.String(r.tableName),
+		KeyConditions: map[string]types.Condition{
+			"season": {
+				ComparisonOperator: types.ComparisonOperatorEq,
+				AttributeValueList: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: season},
+				},
+			},
+			"date": {
+				ComparisonOperator: types.ComparisonOperatorLe,
+				AttributeValueList: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: date},
+				},
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+	}
+
+	queryOutput, err := r.client.Query(ctx, queryInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query snapshot items")
+	}
+
+	if queryOutput == nil || queryOutput.Items == nil || len(queryOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	snapshot := &LeaderboardSnapshot{}
+
+	err = attributevalue.UnmarshalMap(queryOutput.Items[0], snapshot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal snapshot item")
+	}
+
+	return snapshot, nil
+}
+
+func (r *SnapshotDB) Save(ctx context.Context, snapshot *LeaderboardSnapshot) error {
+	snapshot.TypeName = "LeaderboardSnapshot"
+
+	if snapshot.Season == "" {
+		return errors.New("season is required")
+	}
+
+	if snapshot.Date == "" {
+		return errors.New("date is required")
+	}
+
+	snapshot.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	avMap, err := attributevalue.MarshalMap(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal snapshot item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put snapshot item")
+	}
+
+	return nil
+}
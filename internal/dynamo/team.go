@@ -0,0 +1,113 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/pkg/errors"
+)
+
+var _ TeamRepo = (*TeamDB)(nil)
+
+type TeamDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+type Team struct {
+	TypeName  string   `dynamodbav:"__typename"`
+	ID        string   `dynamodbav:"id"`
+	Name      string   `dynamodbav:"name"`
+	MemberIDs []string `dynamodbav:"memberIds"`
+	CreatedAt string   `dynamodbav:"createdAt"`
+	UpdatedAt string   `dynamodbav:"updatedAt"`
+}
+
+func NewTeamRepo(client *dynamodb.Client, tableName string) *TeamDB {
+	return &TeamDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *TeamDB) GetAll(ctx context.Context) ([]Team, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	scanOutput, err := r.client.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not scan team items")
+	}
+
+	if scanOutput == nil || scanOutput.Items == nil || len(scanOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	teams := []Team{}
+
+	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &teams)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal team items")
+	}
+
+	return teams, nil
+}
+
+func (r *TeamDB) Save(ctx context.Context, team *Team) error {
+	team.TypeName = "Team"
+
+	if team.Name == "" {
+		return errors.New("name is required")
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	if team.ID == "" {
+		id, err := gonanoid.New()
+		if err != nil {
+			return errors.Wrap(err, "could not create uuid")
+		}
+
+		team.ID = id
+		team.CreatedAt = now
+	}
+
+	team.UpdatedAt = now
+
+	avMap, err := attributevalue.MarshalMap(team)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal team item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put team item")
+	}
+
+	return nil
+}
+
+func (r *TeamDB) Delete(ctx context.Context, id string) error {
+	deleteItemInput := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	}
+
+	if _, err := r.client.DeleteItem(ctx, deleteItemInput); err != nil {
+		return errors.Wrap(err, "could delete team item")
+	}
+
+	return nil
+}
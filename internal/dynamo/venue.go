@@ -0,0 +1,183 @@
+package dynamo
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+var _ VenueRepo = (*VenueDB)(nil)
+
+type VenueDB struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// Venue is an Untappd venue a guild follows, known in the guild by its name. Path is the part of the venue's
+// Untappd URL after /v/. When WatchChannelID is set, changes to the venue's tap list are announced in that channel,
+// and TapList holds the beers on tap when it was last checked.
+type Venue struct {
+	TypeName       string    `dynamodbav:"__typename"`
+	GuildID        string    `dynamodbav:"guildId"`
+	Name           string    `dynamodbav:"name"`
+	Path           string    `dynamodbav:"path"`
+	WatchChannelID string    `dynamodbav:"watchChannelId"`
+	TapList        []TapBeer `dynamodbav:"tapList"`
+	CheckedAt      string    `dynamodbav:"checkedAt"`
+	CreatedAt      string    `dynamodbav:"createdAt"`
+	UpdatedAt      string    `dynamodbav:"updatedAt"`
+}
+
+// TapBeer is a beer on a venue's tap list.
+type TapBeer struct {
+	Name    string `dynamodbav:"name"`
+	Brewery string `dynamodbav:"brewery"`
+	Style   string `dynamodbav:"style"`
+	Menu    string `dynamodbav:"menu"`
+}
+
+func NewVenueRepo(client *dynamodb.Client, tableName string) *VenueDB {
+	return &VenueDB{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *VenueDB) Get(ctx context.Context, guildID, name string) (*Venue, error) {
+	getItemInput := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"guildId": &types.AttributeValueMemberS{Value: guildID},
+			"name":    &types.AttributeValueMemberS{Value: name},
+		},
+	}
+
+	getItemOutput, err := r.client.GetItem(ctx, getItemInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get venue item")
+	}
+
+	if getItemOutput.Item == nil || len(getItemOutput.Item) == 0 {
+		return nil, nil
+	}
+
+	venue := &Venue{}
+
+	err = attributevalue.UnmarshalMap(getItemOutput.Item, venue)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal venue item")
+	}
+
+	return venue, nil
+}
+
+func (r *VenueDB) GetByGuildID(ctx context.Context, guildID string) ([]Venue, error) {
+	queryInput := &dynamodb.QueryInput{
+		TableName: aws.String(r.tableName),
+		KeyConditions: map[string]types.Condition{
+			"guildId": {
+				ComparisonOperator: types.ComparisonOperatorEq,
+				AttributeValueList: []types.AttributeValue{
+					&types.AttributeValueMemberS{Value: guildID},
+				},
+			},
+		},
+	}
+
+	queryOutput, err := r.client.Query(ctx, queryInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query venue items")
+	}
+
+	if queryOutput == nil || queryOutput.Items == nil || len(queryOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	venues := []Venue{}
+
+	err = attributevalue.UnmarshalListOfMaps(queryOutput.Items, &venues)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal venue items")
+	}
+
+	return venues, nil
+}
+
+func (r *VenueDB) GetAll(ctx context.Context) ([]Venue, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	scanOutput, err := r.client.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not scan venue items")
+	}
+
+	if scanOutput == nil || scanOutput.Items == nil || len(scanOutput.Items) == 0 {
+		return nil, nil
+	}
+
+	venues := []Venue{}
+
+	err = attributevalue.UnmarshalListOfMaps(scanOutput.Items, &venues)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal venue items")
+	}
+
+	return venues, nil
+}
+
+func (r *VenueDB) Save(ctx context.Context, venue *Venue) error {
+	venue.TypeName = "Venue"
+
+	if venue.GuildID == "" {
+		return errors.New("guildId is required")
+	}
+
+	if venue.Name == "" {
+		return errors.New("name is required")
+	}
+
+	venue.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if venue.CreatedAt == "" {
+		venue.CreatedAt = venue.UpdatedAt
+	}
+
+	avMap, err := attributevalue.MarshalMap(venue)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal venue item")
+	}
+
+	putItemInput := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      avMap,
+	}
+
+	if _, err := r.client.PutItem(ctx, putItemInput); err != nil {
+		return errors.Wrap(err, "could put venue item")
+	}
+
+	return nil
+}
+
+func (r *VenueDB) Delete(ctx context.Context, guildID, name string) error {
+	deleteItemInput := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"guildId": &types.AttributeValueMemberS{Value: guildID},
+			"name":    &types.AttributeValueMemberS{Value: name},
+		},
+	}
+
+	if _, err := r.client.DeleteItem(ctx, deleteItemInput); err != nil {
+		return errors.Wrap(err, "could delete venue item")
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package discord
 
 import (
+	"strings"
+
 	"github.com/bwmarrin/discordgo"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -9,18 +11,24 @@ import (
 type HandlerFunc func(s *discordgo.Session, i *discordgo.InteractionCreate) error
 
 type Bot struct {
-	session  *discordgo.Session
-	guildID  string
-	handlers map[string]HandlerFunc
-	logger   *logrus.Logger
+	session              *discordgo.Session
+	guildID              string
+	handlers             map[string]HandlerFunc
+	autocompleteHandlers map[string]HandlerFunc
+	componentHandlers    map[string]HandlerFunc
+	modalHandlers        map[string]HandlerFunc
+	logger               *logrus.Logger
 }
 
 func NewBot(session *discordgo.Session, guildID string, logger *logrus.Logger) *Bot {
 	bot := &Bot{
-		session:  session,
-		guildID:  guildID,
-		logger:   logger,
-		handlers: make(map[string]HandlerFunc),
+		session:              session,
+		guildID:              guildID,
+		logger:               logger,
+		handlers:             make(map[string]HandlerFunc),
+		autocompleteHandlers: make(map[string]HandlerFunc),
+		componentHandlers:    make(map[string]HandlerFunc),
+		modalHandlers:        make(map[string]HandlerFunc),
 	}
 
 	// session.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
@@ -28,9 +36,32 @@ func NewBot(session *discordgo.Session, guildID string, logger *logrus.Logger) *
 	// })
 
 	session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if handler, ok := bot.handlers[i.ApplicationCommandData().Name]; ok {
-			if err := handler(s, i); err != nil {
-				logger.WithError(err).Errorf("could not handle '%s' command", i.ApplicationCommandData().Name)
+		switch i.Type { //nolint: exhaustive
+		case discordgo.InteractionApplicationCommand:
+			if handler, ok := bot.handlers[i.ApplicationCommandData().Name]; ok {
+				if err := handler(s, i); err != nil {
+					logger.WithError(err).Errorf("could not handle '%s' command", i.ApplicationCommandData().Name)
+				}
+			}
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			if handler, ok := bot.autocompleteHandlers[i.ApplicationCommandData().Name]; ok {
+				if err := handler(s, i); err != nil {
+					logger.WithError(err).Errorf("could not autocomplete '%s' command", i.ApplicationCommandData().Name)
+				}
+			}
+		case discordgo.InteractionMessageComponent:
+			prefix := componentPrefix(i.MessageComponentData().CustomID)
+			if handler, ok := bot.componentHandlers[prefix]; ok {
+				if err := handler(s, i); err != nil {
+					logger.WithError(err).Errorf("could not handle '%s' component", prefix)
+				}
+			}
+		case discordgo.InteractionModalSubmit:
+			prefix := componentPrefix(i.ModalSubmitData().CustomID)
+			if handler, ok := bot.modalHandlers[prefix]; ok {
+				if err := handler(s, i); err != nil {
+					logger.WithError(err).Errorf("could not handle '%s' modal", prefix)
+				}
 			}
 		}
 	})
@@ -50,6 +81,39 @@ func (b *Bot) AddHandler(name string, handlerFunc HandlerFunc) {
 	b.handlers[name] = handlerFunc
 }
 
+func (b *Bot) AddAutocompleteHandler(name string, handlerFunc HandlerFunc) {
+	b.autocompleteHandlers[name] = handlerFunc
+}
+
+// AddComponentHandler routes message component interactions, such as button clicks, whose custom ID starts with
+// prefix followed by a colon. The rest of the custom ID is left for the handler to carry its own state.
+func (b *Bot) AddComponentHandler(prefix string, handlerFunc HandlerFunc) {
+	b.componentHandlers[prefix] = handlerFunc
+}
+
+// AddModalHandler routes modal submissions whose custom ID starts with prefix followed by a colon, in the same way
+// as AddComponentHandler.
+func (b *Bot) AddModalHandler(prefix string, handlerFunc HandlerFunc) {
+	b.modalHandlers[prefix] = handlerFunc
+}
+
+func (b *Bot) SendMessage(channelID, message string) error {
+	if _, err := b.session.ChannelMessageSend(channelID, message); err != nil {
+		return errors.Wrapf(err, "could not send message to channel %s", channelID)
+	}
+
+	return nil
+}
+
+func (b *Bot) SendDirectMessage(userID, message string) error {
+	channel, err := b.session.UserChannelCreate(userID)
+	if err != nil {
+		return errors.Wrapf(err, "could not create DM channel for user %s", userID)
+	}
+
+	return b.SendMessage(channel.ID, message)
+}
+
 func (b *Bot) RemoveAllCommands() error {
 	commands, err := b.session.ApplicationCommands(b.session.State.User.ID, b.guildID)
 	if err != nil {
@@ -63,3 +127,7 @@ func (b *Bot) RemoveAllCommands() error {
 	}
 	return nil
 }
+
+func componentPrefix(customID string) string {
+	return strings.SplitN(customID, ":", 2)[0] //nolint: gomnd
+}
@@ -0,0 +1,96 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/pkg/errors"
+)
+
+const membersPageSize = 1000
+
+// RoleManager keeps the members of the Bot's guild holding a role in sync with the set of users who should hold it.
+// Listing guild members requires the Server Members privileged intent to be enabled for the application.
+type RoleManager struct {
+	bot *Bot
+}
+
+func NewRoleManager(bot *Bot) *RoleManager {
+	return &RoleManager{
+		bot: bot,
+	}
+}
+
+// Reconcile grants each role to exactly the users it is mapped to, removing it from every other member.
+func (m *RoleManager) Reconcile(desired map[string][]string) error {
+	if len(desired) == 0 {
+		return nil
+	}
+
+	members, err := m.members()
+	if err != nil {
+		return errors.Wrap(err, "could not get guild members")
+	}
+
+	for roleID, userIDs := range desired {
+		if err := m.reconcileRole(members, roleID, userIDs); err != nil {
+			return errors.Wrapf(err, "could not reconcile role %s", roleID)
+		}
+	}
+
+	return nil
+}
+
+func (m *RoleManager) reconcileRole(members []*discordgo.Member, roleID string, userIDs []string) error {
+	want := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		want[userID] = true
+	}
+
+	for _, member := range members {
+		has := hasRole(member, roleID)
+
+		switch {
+		case has && !want[member.User.ID]:
+			if err := m.bot.session.GuildMemberRoleRemove(m.bot.guildID, member.User.ID, roleID); err != nil {
+				return errors.Wrapf(err, "could not remove role from user %s", member.User.ID)
+			}
+		case !has && want[member.User.ID]:
+			if err := m.bot.session.GuildMemberRoleAdd(m.bot.guildID, member.User.ID, roleID); err != nil {
+				return errors.Wrapf(err, "could not add role to user %s", member.User.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *RoleManager) members() ([]*discordgo.Member, error) {
+	var (
+		members []*discordgo.Member
+		after   string
+	)
+
+	for {
+		page, err := m.bot.session.GuildMembers(m.bot.guildID, after, membersPageSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list guild members")
+		}
+
+		members = append(members, page...)
+
+		if len(page) < membersPageSize {
+			return members, nil
+		}
+
+		after = page[len(page)-1].User.ID
+	}
+}
+
+func hasRole(member *discordgo.Member, roleID string) bool {
+	for _, id := range member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+
+	return false
+}
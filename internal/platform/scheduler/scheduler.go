@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JobFunc is run by the Scheduler on every tick. Jobs are responsible for deciding whether there is any work due
+// at the given time, which allows their schedules to be persisted and survive restarts.
+type JobFunc func(ctx context.Context, now time.Time) error
+
+type Scheduler struct {
+	interval time.Duration
+	jobs     map[string]JobFunc
+	mu       sync.Mutex
+	logger   *logrus.Logger
+}
+
+func New(interval time.Duration, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		jobs:     make(map[string]JobFunc),
+		logger:   logger,
+	}
+}
+
+func (s *Scheduler) AddJob(name string, jobFunc JobFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[name] = jobFunc
+}
+
+// Run blocks, running every job once per interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runJobs(ctx, now.UTC())
+		}
+	}
+}
+
+func (s *Scheduler) runJobs(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	jobs := make(map[string]JobFunc, len(s.jobs))
+
+	for name, job := range s.jobs {
+		jobs[name] = job
+	}
+	s.mu.Unlock()
+
+	for name, job := range jobs {
+		if err := job(ctx, now); err != nil {
+			s.logger.WithError(err).Errorf("could not run '%s' job", name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package styles
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// abvFactor converts the drop in gravity during fermentation into an approximate ABV.
+const abvFactor = 131.25
+
+// Recipe holds the vital statistics of a recipe, to be checked against the ranges of a style. The bitterness and
+// color are optional, as they mean nothing for meads and ciders, and are left unchecked when NoIBU or NoSRM is set.
+type Recipe struct {
+	IBU   float64
+	OG    float64
+	FG    float64
+	ABV   float64
+	SRM   float64
+	NoIBU bool
+	NoSRM bool
+}
+
+// Fit is a style along with how far a recipe is from it. A Distance of 0 means the recipe is within every range.
+type Fit struct {
+	Style    Style
+	Distance float64
+}
+
+// EstimateABV approximates the ABV of a beer from its original and final gravity.
+func EstimateABV(og, fg float64) float64 {
+	return (og - fg) * abvFactor
+}
+
+// Deviation returns how far value is outside the range, which is negative below it, positive above it and 0 within
+// it or when the range is not applicable.
+func (r Range) Deviation(value float64) float64 {
+	switch {
+	case !r.Applicable:
+		return 0
+	case value < r.Min:
+		return value - r.Min
+	case value > r.Max:
+		return value - r.Max
+	}
+
+	return 0
+}
+
+// Distance measures how far the recipe is from the style, summing the deviation of each vital relative to the width
+// of its range so that a gravity point off counts as much as a few IBUs off. Vitals that are not applicable to the
+// style, or not known for the recipe, are ignored.
+func (s Style) Distance(recipe Recipe) float64 {
+	var distance float64
+
+	for _, v := range []struct {
+		r     Range
+		value float64
+		known bool
+	}{
+		{r: s.IBU, value: recipe.IBU, known: !recipe.NoIBU},
+		{r: s.OG, value: recipe.OG, known: true},
+		{r: s.FG, value: recipe.FG, known: true},
+		{r: s.ABV, value: recipe.ABV, known: true},
+		{r: s.SRM, value: recipe.SRM, known: !recipe.NoSRM},
+	} {
+		if !v.r.Applicable || !v.known {
+			continue
+		}
+
+		width := v.r.Max - v.r.Min
+		if width == 0 {
+			width = math.Max(math.Abs(v.r.Max), 1)
+		}
+
+		distance += math.Abs(v.r.Deviation(v.value)) / width
+	}
+
+	return distance
+}
+
+// Fits ranks the styles by how close the recipe is to them, closest first. Styles without a range for every vital the
+// recipe gives, such as specialty styles, are left out because the recipe could not be measured against all of its
+// numbers.
+func (s *StyleSource) Fits(ctx context.Context, recipe Recipe) []Fit {
+	fits := make([]Fit, 0, len(s.styles))
+
+	for _, style := range s.styles {
+		if !style.OG.Applicable || !style.FG.Applicable || !style.ABV.Applicable ||
+			(!recipe.NoIBU && !style.IBU.Applicable) || (!recipe.NoSRM && !style.SRM.Applicable) {
+			continue
+		}
+
+		fits = append(fits, Fit{Style: style, Distance: style.Distance(recipe)})
+	}
+
+	sort.Slice(fits, func(i, j int) bool {
+		if fits[i].Distance != fits[j].Distance {
+			return fits[i].Distance < fits[j].Distance
+		}
+
+		return lessNumber(fits[i].Style.Number, fits[j].Style.Number)
+	})
+
+	return fits
+}
@@ -0,0 +1,32 @@
+package styles
+
+import "math"
+
+// srmColors approximates the color of beer at each SRM from 1 to 40 as RGB.
+//
+//nolint:gochecknoglobals
+var srmColors = []int{
+	0xFFE699, 0xFFD878, 0xFFCA5A, 0xFFBF42, 0xFBB123, 0xF8A600, 0xF39C00, 0xEA8F00, 0xE58500, 0xDE7C00,
+	0xD77200, 0xCF6900, 0xCB6200, 0xC35900, 0xBB5100, 0xB54C00, 0xB04500, 0xA63E00, 0xA13700, 0x9B3200,
+	0x952D00, 0x8E2900, 0x882300, 0x821E00, 0x7B1A00, 0x771900, 0x701400, 0x6A0E00, 0x660D00, 0x5E0B00,
+	0x5A0A02, 0x600903, 0x520907, 0x4C0505, 0x470606, 0x440607, 0x3F0708, 0x3B0607, 0x3A070B, 0x36080A,
+}
+
+// Color returns the RGB color of the middle of the style's SRM range, clamped to the chart. Styles without an
+// applicable SRM range, such as specialty styles, have no color and return 0.
+func (s Style) Color() int {
+	if !s.SRM.Applicable {
+		return 0
+	}
+
+	srm := int(math.Round((s.SRM.Min + s.SRM.Max) / 2)) //nolint: gomnd
+
+	switch {
+	case srm < 1:
+		srm = 1
+	case srm > len(srmColors):
+		srm = len(srmColors)
+	}
+
+	return srmColors[srm-1]
+}
@@ -0,0 +1,75 @@
+package styles
+
+import "strings"
+
+const specialtyTag = "specialty-beer"
+
+// RandomFilter narrows the styles that Random picks from. The zero value allows every style.
+type RandomFilter struct {
+	// Category matches either the category name or number, ignoring case.
+	Category string
+	// Tags must all be present on the style, ignoring how they are spelled.
+	Tags []string
+	// MaxABV excludes styles whose minimum ABV is above it, when set.
+	MaxABV *float64
+	// ExcludeSpecialty excludes styles tagged as specialty beers.
+	ExcludeSpecialty bool
+	// ExcludeNumbers excludes specific styles, such as the ones a user has already brewed.
+	ExcludeNumbers []string
+}
+
+// Allows reports whether the style satisfies every constraint of the filter.
+func (f RandomFilter) Allows(style Style) bool {
+	if f.Category != "" && !strings.EqualFold(style.Category, f.Category) &&
+		!strings.EqualFold(style.CategoryNumber, f.Category) {
+		return false
+	}
+
+	tags := style.TagList()
+
+	for _, tag := range f.Tags {
+		if !containsTag(tags, tag) {
+			return false
+		}
+	}
+
+	if f.MaxABV != nil && (!style.ABV.Applicable || style.ABV.Min > *f.MaxABV) {
+		return false
+	}
+
+	if f.ExcludeSpecialty && containsTag(tags, specialtyTag) {
+		return false
+	}
+
+	for _, number := range f.ExcludeNumbers {
+		if strings.EqualFold(style.Number, number) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TagList splits the comma-separated Tags of the style.
+func (s Style) TagList() []string {
+	tags := []string{}
+
+	for _, tag := range strings.Split(s.Tags, ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// containsTag reports whether tag is one of tags, however either is spelled.
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if tagKey(t) == tagKey(tag) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,291 @@
+package styles
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+)
+
+const (
+	guideExtension = ".json"
+	embeddedDir    = "guides"
+)
+
+// embeddedGuides are the guides built into the binary, so the bot runs from any working directory.
+//
+//go:embed guides/*.json
+var embeddedGuides embed.FS //nolint:gochecknoglobals
+
+var _ GuideRepo = (*GuideSource)(nil)
+
+// GuideSource is a registry of style guides, such as the BJCP beer, mead and cider guidelines, each named after the
+// file it was loaded from. The guides built into the binary can be overridden, or added to, by the guides in an
+// override directory, which are reloaded when they change.
+type GuideSource struct {
+	mu          sync.RWMutex
+	embedded    map[string]*StyleSource
+	guides      map[string]*StyleSource
+	names       []string
+	overrideDir string
+
+	// reloadMu serializes reloads, which are the only writers of guides, and guards modTimes.
+	reloadMu sync.Mutex
+	modTimes map[string]time.Time
+}
+
+// NewGuideRepo loads the embedded guides and then the guides in overrideDir, if it is not empty. A guide that fails
+// to load or validate fails the whole registry so that a broken guide is caught at startup instead of when someone
+// asks for it.
+func NewGuideRepo(overrideDir string) (*GuideSource, error) {
+	g := GuideSource{
+		embedded:    make(map[string]*StyleSource),
+		guides:      make(map[string]*StyleSource),
+		overrideDir: overrideDir,
+		modTimes:    make(map[string]time.Time),
+	}
+
+	entries, err := embeddedGuides.ReadDir(embeddedDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read embedded guides")
+	}
+
+	for _, entry := range entries {
+		data, err := fs.ReadFile(embeddedGuides, path.Join(embeddedDir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read embedded guide %s", entry.Name())
+		}
+
+		name := strings.TrimSuffix(entry.Name(), guideExtension)
+
+		guide, err := parseStyles(data, entry.Name())
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not load embedded guide %s", name)
+		}
+
+		g.embedded[name] = guide
+		g.guides[name] = guide
+	}
+
+	if overrideDir != "" {
+		files, err := g.overrideFiles()
+		if err != nil {
+			return nil, err
+		}
+
+		for name, modTime := range files {
+			guide, err := NewStyleRepo(filepath.Join(overrideDir, name+guideExtension))
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not load guide %s", name)
+			}
+
+			g.guides[name] = guide
+			g.modTimes[name] = modTime
+		}
+	}
+
+	g.names = guideNames(g.guides)
+
+	return &g, nil
+}
+
+// Guide returns the named guide, or nil if there is no such guide.
+func (g *GuideSource) Guide(name string) StyleRepo {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	guide, ok := g.guides[name]
+	if !ok {
+		return nil
+	}
+
+	return guide
+}
+
+// Names returns the names of the loaded guides in alphabetical order.
+func (g *GuideSource) Names() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return append([]string(nil), g.names...)
+}
+
+// Watch reloads the guides in the override directory whenever a file in it changes, until ctx is done. Reload
+// errors are passed to onError rather than stopping the watch, so a guide that fails to validate can be fixed in
+// place. Watch does nothing when there is no override directory.
+func (g *GuideSource) Watch(ctx context.Context, onError func(error)) error {
+	if g.overrideDir == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "could not create guide watcher")
+	}
+
+	if err := watcher.Add(g.overrideDir); err != nil {
+		watcher.Close()
+
+		return errors.Wrapf(err, "could not watch guide directory %s", g.overrideDir)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filepath.Ext(event.Name) != guideExtension || event.Has(fsnotify.Chmod) {
+					continue
+				}
+
+				if err := g.Reload(ctx); err != nil {
+					onError(err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				onError(errors.Wrap(err, "could not watch guide directory"))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Reload reloads the guides in the override directory that have been added, changed or removed since they were last
+// loaded. The changed guides are parsed before the lock is taken, and the guides are then swapped in together, so
+// lookups are never blocked by a parse. A guide that fails to validate keeps serving its previous data and is
+// retried when it next changes. A removed override falls back to the embedded guide of the same name, if there is
+// one.
+func (g *GuideSource) Reload(ctx context.Context) error {
+	if g.overrideDir == "" {
+		return nil
+	}
+
+	g.reloadMu.Lock()
+	defer g.reloadMu.Unlock()
+
+	files, err := g.overrideFiles()
+	if err != nil {
+		return err
+	}
+
+	g.mu.RLock()
+	guides := make(map[string]*StyleSource, len(g.guides))
+
+	for name, guide := range g.guides {
+		guides[name] = guide
+	}
+	g.mu.RUnlock()
+
+	var (
+		problems []string
+		changed  bool
+	)
+
+	for name, modTime := range files {
+		if loaded, ok := g.modTimes[name]; ok && loaded.Equal(modTime) {
+			continue
+		}
+
+		// Record the attempt even when it fails so a broken guide is reported once rather than on every change to
+		// another guide.
+		g.modTimes[name] = modTime
+
+		guide, err := NewStyleRepo(filepath.Join(g.overrideDir, name+guideExtension))
+		if err != nil {
+			problems = append(problems, errors.Wrapf(err, "could not reload guide %s", name).Error())
+
+			continue
+		}
+
+		guides[name] = guide
+		changed = true
+	}
+
+	for name := range g.modTimes {
+		if _, ok := files[name]; ok {
+			continue
+		}
+
+		delete(g.modTimes, name)
+
+		if embedded, ok := g.embedded[name]; ok {
+			guides[name] = embedded
+		} else {
+			delete(guides, name)
+		}
+
+		changed = true
+	}
+
+	if changed {
+		names := guideNames(guides)
+
+		g.mu.Lock()
+		g.guides = guides
+		g.names = names
+		g.mu.Unlock()
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// overrideFiles returns the modification time of each guide in the override directory, keyed by guide name.
+func (g *GuideSource) overrideFiles() (map[string]time.Time, error) {
+	entries, err := os.ReadDir(g.overrideDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read guide directory %s", g.overrideDir)
+	}
+
+	files := make(map[string]time.Time, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != guideExtension {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not stat guide %s", entry.Name())
+		}
+
+		files[strings.TrimSuffix(entry.Name(), guideExtension)] = info.ModTime()
+	}
+
+	return files, nil
+}
+
+func guideNames(guides map[string]*StyleSource) []string {
+	names := make([]string, 0, len(guides))
+
+	for name := range guides {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
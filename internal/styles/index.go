@@ -0,0 +1,116 @@
+package styles
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Category is a category of the style guide along with its styles, ordered by style number.
+type Category struct {
+	Number string
+	Name   string
+	Styles []Style
+}
+
+// index groups the styles by category and tag so that browsing them does not require a scan of the whole guide.
+func (s *StyleSource) index() {
+	sorted := make([]Style, 0, len(s.styles))
+	for _, style := range s.styles {
+		sorted = append(sorted, style)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return lessNumber(sorted[i].Number, sorted[j].Number)
+	})
+
+	categories := map[string]*Category{}
+	spellings := map[string]map[string]int{}
+
+	s.categories = nil
+	s.tags = make(map[string][]Style)
+	s.tagNames = nil
+
+	for _, style := range sorted {
+		category, ok := categories[style.CategoryNumber]
+		if !ok {
+			category = &Category{Number: style.CategoryNumber, Name: style.Category}
+			categories[style.CategoryNumber] = category
+		}
+
+		category.Styles = append(category.Styles, style)
+
+		seen := map[string]bool{}
+
+		for _, tag := range style.TagList() {
+			key := tagKey(tag)
+
+			if spellings[key] == nil {
+				spellings[key] = map[string]int{}
+			}
+
+			spellings[key][tag]++
+
+			if !seen[key] {
+				seen[key] = true
+				s.tags[key] = append(s.tags[key], style)
+			}
+		}
+	}
+
+	for _, category := range categories {
+		s.categories = append(s.categories, *category)
+	}
+
+	sort.Slice(s.categories, func(i, j int) bool {
+		return lessNumber(s.categories[i].Number, s.categories[j].Number)
+	})
+
+	for _, counts := range spellings {
+		s.tagNames = append(s.tagNames, commonSpelling(counts))
+	}
+
+	sort.Strings(s.tagNames)
+}
+
+// Categories returns every category in the guide, ordered by category number.
+func (s *StyleSource) Categories(ctx context.Context) []Category {
+	return s.categories
+}
+
+// Tags returns the name of every tag in the guide in alphabetical order. The guides spell some tags several ways,
+// such as "north-america" and "northamerica", in which case the most common spelling is used.
+func (s *StyleSource) Tags(ctx context.Context) []string {
+	return s.tagNames
+}
+
+// ByTag returns the styles carrying the tag, however it is spelled, ordered by style number.
+func (s *StyleSource) ByTag(ctx context.Context, tag string) []Style {
+	return s.tags[tagKey(tag)]
+}
+
+// tagKey identifies a tag regardless of case and separators, so that "North America", "north-america" and
+// "northamerica" are the same tag.
+func tagKey(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if isSeparator(r) {
+			return -1
+		}
+
+		return unicode.ToLower(r)
+	}, tag)
+}
+
+// commonSpelling returns the most used spelling of a tag, breaking ties alphabetically so the result is stable.
+func commonSpelling(counts map[string]int) string {
+	var best string
+
+	for spelling, count := range counts {
+		if best == "" || count > counts[best] || count == counts[best] && spelling < best {
+			best = spelling
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,120 @@
+package styles
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	quizChoices     = 4
+	quizCandidates  = 8
+	redactedText    = "▒▒▒"
+	minRedactLength = 3
+)
+
+// descriptiveWords are words in style names that also describe beers in general, such as colors and strengths, so
+// redacting them would hide more from a description than the name of the style.
+//
+//nolint:gochecknoglobals
+var descriptiveWords = map[string]bool{
+	"ale": true, "beer": true, "lager": true, "and": true, "the": true, "style": true, "pale": true, "dark": true,
+	"light": true, "amber": true, "brown": true, "red": true, "golden": true, "blonde": true, "strong": true,
+	"wild": true, "sour": true, "black": true, "white": true, "wheat": true, "malty": true, "hoppy": true,
+	"mead": true, "cider": true, "perry": true, "fruit": true, "spice": true,
+}
+
+// Quiz is a style to identify from its description, along with the styles offered as answers.
+type Quiz struct {
+	Style   Style
+	Choices []Style
+	Answer  int
+}
+
+// Quiz picks a random style with a description to identify and three of the styles most like it to offer alongside
+// it, so that the vitals alone rarely give the answer away. Specialty styles are left out because they are defined
+// by their entry rather than their description. The same seed always builds the same quiz.
+//
+// Styles of guides that do not describe their aroma and appearance are quizzed on their overall impression instead.
+func (s *StyleSource) Quiz(ctx context.Context, seed int64) *Quiz {
+	candidates := make([]Style, 0, len(s.styles))
+
+	filter := RandomFilter{ExcludeSpecialty: true}
+
+	for _, style := range s.styles {
+		if filter.Allows(style) && (style.Aroma != "" || style.OverallImpression != "") {
+			candidates = append(candidates, style)
+		}
+	}
+
+	if len(candidates) < quizChoices {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return lessNumber(candidates[i].Number, candidates[j].Number)
+	})
+
+	//nolint: gosec
+	r := rand.New(rand.NewSource(seed))
+	style := candidates[r.Intn(len(candidates))]
+
+	others := make([]Style, 0, quizCandidates)
+
+	for _, similarity := range s.Similar(ctx, style.Number) {
+		if filter.Allows(similarity.Style) {
+			others = append(others, similarity.Style)
+		}
+
+		if len(others) == quizCandidates {
+			break
+		}
+	}
+
+	r.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+
+	choices := append([]Style{style}, others[:quizChoices-1]...)
+	r.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+
+	quiz := &Quiz{Style: style, Choices: choices}
+
+	for n, choice := range choices {
+		if choice.Number == style.Number {
+			quiz.Answer = n
+		}
+	}
+
+	return quiz
+}
+
+// Redact hides the name, number and category of the style, and the distinctive words of its name, wherever they
+// appear in text, so the text can describe the style without giving it away.
+func (s Style) Redact(text string) string {
+	terms := []string{s.Name, s.Number, s.Category}
+
+	for _, word := range strings.FieldsFunc(s.Name, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if len([]rune(word)) >= minRedactLength && !descriptiveWords[strings.ToLower(word)] {
+			terms = append(terms, word)
+		}
+	}
+
+	// Longer terms go first so that a full name is redacted as one rather than word by word.
+	sort.Slice(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+
+	quoted := make([]string, 0, len(terms))
+
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+
+	if len(quoted) == 0 {
+		return text
+	}
+
+	return regexp.MustCompile(`(?i)\b(`+strings.Join(quoted, "|")+`)\b`).ReplaceAllString(text, redactedText)
+}
@@ -3,6 +3,21 @@ package styles
 import "context"
 
 type StyleRepo interface {
-	Random(ctx context.Context) Style
+	Random(ctx context.Context, filter RandomFilter, seed int64) *Style
 	Get(ctx context.Context, number string) *Style
+	Search(ctx context.Context, query string) []Style
+	Find(ctx context.Context, filter VitalsFilter) []Style
+	Categories(ctx context.Context) []Category
+	Tags(ctx context.Context) []string
+	ByTag(ctx context.Context, tag string) []Style
+	Fits(ctx context.Context, recipe Recipe) []Fit
+	Similar(ctx context.Context, number string) []Similarity
+	Suggest(ctx context.Context, brewed map[string]float64) []Suggestion
+	Quiz(ctx context.Context, seed int64) *Quiz
+	FromUntappd(ctx context.Context, untappdStyle string) *Style
+}
+
+type GuideRepo interface {
+	Guide(name string) StyleRepo
+	Names() []string
 }
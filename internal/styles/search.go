@@ -0,0 +1,206 @@
+package styles
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	numberScore         = 100
+	exactNameScore      = 50
+	namePrefixScore     = 30
+	nameContainsScore   = 20
+	nameTokenScore      = 10
+	fuzzyNameTokenScore = 5
+	categoryTokenScore  = 4
+	tagTokenScore       = 3
+	exampleTokenScore   = 2
+	fuzzyExampleScore   = 1
+	minFuzzyTokenLength = 4
+	longTokenLength     = 8
+)
+
+//nolint:gochecknoglobals
+var accentReplacer = strings.NewReplacer("ä", "a", "á", "a", "à", "a", "ö", "o", "ó", "o", "ü", "u", "ú", "u",
+	"é", "e", "è", "e", "ê", "e", "í", "i", "ñ", "n", "ß", "ss")
+
+type scoredStyle struct {
+	style Style
+	score int
+}
+
+// Search ranks the styles by how well their name, category, tags and commercial examples match the query, with
+// tolerance for small typos. Styles that do not match at all are omitted. An empty query returns every style.
+func (s *StyleSource) Search(ctx context.Context, query string) []Style {
+	query = normalize(query)
+	tokens := strings.Fields(query)
+
+	scored := make([]scoredStyle, 0, len(s.styles))
+
+	for _, style := range s.styles {
+		score := scoreStyle(style, query, tokens)
+		if score > 0 || query == "" {
+			scored = append(scored, scoredStyle{style: style, score: score})
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score != scored[j].score {
+			return scored[i].score > scored[j].score
+		}
+
+		return lessNumber(scored[i].style.Number, scored[j].style.Number)
+	})
+
+	results := make([]Style, 0, len(scored))
+	for _, s := range scored {
+		results = append(results, s.style)
+	}
+
+	return results
+}
+
+func scoreStyle(style Style, query string, tokens []string) int {
+	if query == "" {
+		return 0
+	}
+
+	var score int
+
+	if strings.EqualFold(style.Number, query) {
+		score += numberScore
+	}
+
+	name := normalize(style.Name)
+
+	switch {
+	case name == query:
+		score += exactNameScore
+	case strings.HasPrefix(name, query):
+		score += namePrefixScore
+	case strings.Contains(name, query):
+		score += nameContainsScore
+	}
+
+	nameWords := strings.FieldsFunc(name, isSeparator)
+	category := normalize(style.Category)
+	tags := strings.FieldsFunc(normalize(style.Tags), isSeparator)
+	examples := strings.FieldsFunc(normalize(style.CommercialExamples), isSeparator)
+
+	for _, token := range tokens {
+		score += scoreWords(nameWords, token, nameTokenScore, fuzzyNameTokenScore)
+
+		if strings.Contains(category, token) {
+			score += categoryTokenScore
+		}
+
+		score += scoreWords(tags, token, tagTokenScore, 0)
+
+		score += scoreWords(examples, token, exampleTokenScore, fuzzyExampleScore)
+	}
+
+	return score
+}
+
+func scoreWords(words []string, token string, exact, fuzzy int) int {
+	for _, word := range words {
+		if strings.HasPrefix(word, token) {
+			return exact
+		}
+	}
+
+	maxDistance := typoDistance(token)
+	if maxDistance == 0 {
+		return 0
+	}
+
+	for _, word := range words {
+		if editDistance(word, token) <= maxDistance {
+			return fuzzy
+		}
+	}
+
+	return 0
+}
+
+// typoDistance is the number of edits a token may be away from a word and still match it. Short tokens must match
+// exactly, since a single edit turns them into too many other words, and long tokens may have two typos.
+func typoDistance(token string) int {
+	switch length := utf8.RuneCountInString(token); {
+	case length < minFuzzyTokenLength:
+		return 0
+	case length < longTokenLength:
+		return 1
+	default:
+		return 2 //nolint: gomnd
+	}
+}
+
+func normalize(s string) string {
+	return strings.TrimSpace(accentReplacer.Replace(strings.ToLower(s)))
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of adjacent letters that turn a into b, so
+// that a transposition such as "wiessbier" for "weissbier" is a single typo.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prevPrev[j-2]+1)
+			}
+		}
+
+		prevPrev, prev, curr = prev, curr, prevPrev
+	}
+
+	return prev[len(rb)]
+}
+
+// lessNumber orders style numbers like "2A" before "10A" by comparing the category number numerically.
+func lessNumber(a, b string) bool {
+	ca, sa := splitNumber(a)
+	cb, sb := splitNumber(b)
+
+	if ca != cb {
+		return ca < cb
+	}
+
+	return sa < sb
+}
+
+func splitNumber(number string) (int, string) {
+	i := strings.IndexFunc(number, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i == -1 {
+		i = len(number)
+	}
+
+	category, _ := strconv.Atoi(number[:i])
+
+	return category, number[i:]
+}
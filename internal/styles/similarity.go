@@ -0,0 +1,180 @@
+package styles
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+const (
+	vitalsWeight   = 0.6
+	tagsWeight     = 0.3
+	categoryWeight = 0.1
+)
+
+// Similarity is a style along with how similar it is to another, from 0 for nothing in common to 1 for identical.
+type Similarity struct {
+	Style Style
+	Score float64
+}
+
+// Suggestion is a style recommended because it is similar to styles that have already been brewed, along with the
+// brewed style it is most similar to.
+type Suggestion struct {
+	Style   Style
+	Score   float64
+	Because Style
+}
+
+// Similarity scores how alike two styles are from how much their vitals overlap, the tags they share and whether
+// they are in the same category.
+func (s Style) Similarity(other Style) float64 {
+	var (
+		vitals   float64
+		compared int
+	)
+
+	for _, pair := range [][2]Range{
+		{s.IBU, other.IBU}, {s.OG, other.OG}, {s.FG, other.FG}, {s.ABV, other.ABV}, {s.SRM, other.SRM},
+	} {
+		if !pair[0].Applicable || !pair[1].Applicable {
+			continue
+		}
+
+		vitals += pair[0].overlapRatio(pair[1])
+		compared++
+	}
+
+	if compared > 0 {
+		vitals /= float64(compared)
+	}
+
+	var category float64
+	if s.CategoryNumber == other.CategoryNumber {
+		category = 1
+	}
+
+	return vitalsWeight*vitals + tagsWeight*tagSimilarity(s.TagList(), other.TagList()) + categoryWeight*category
+}
+
+// Similar ranks every other style by how similar it is to the style with the given number, most similar first.
+func (s *StyleSource) Similar(ctx context.Context, number string) []Similarity {
+	style, ok := s.styles[number]
+	if !ok {
+		return nil
+	}
+
+	similarities := make([]Similarity, 0, len(s.styles))
+
+	for _, other := range s.styles {
+		if other.Number == style.Number {
+			continue
+		}
+
+		similarities = append(similarities, Similarity{Style: other, Score: style.Similarity(other)})
+	}
+
+	sort.Slice(similarities, func(i, j int) bool {
+		if similarities[i].Score != similarities[j].Score {
+			return similarities[i].Score > similarities[j].Score
+		}
+
+		return lessNumber(similarities[i].Style.Number, similarities[j].Style.Number)
+	})
+
+	return similarities
+}
+
+// Suggest ranks the styles that have not been brewed by how similar they are to the ones that have, most similar
+// first. Brewed maps style numbers to how often they were brewed, so that favorites count for more.
+func (s *StyleSource) Suggest(ctx context.Context, brewed map[string]float64) []Suggestion {
+	var total float64
+	for _, weight := range brewed {
+		total += weight
+	}
+
+	suggestions := []Suggestion{}
+
+	if total == 0 {
+		return suggestions
+	}
+
+	for _, candidate := range s.styles {
+		if _, ok := brewed[candidate.Number]; ok {
+			continue
+		}
+
+		suggestion := Suggestion{Style: candidate}
+
+		var closest float64
+
+		for number, weight := range brewed {
+			favorite, ok := s.styles[number]
+			if !ok {
+				continue
+			}
+
+			similarity := candidate.Similarity(favorite)
+			suggestion.Score += weight * similarity / total
+
+			if similarity > closest || similarity == closest && lessNumber(favorite.Number, suggestion.Because.Number) {
+				closest = similarity
+				suggestion.Because = favorite
+			}
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+
+		return lessNumber(suggestions[i].Style.Number, suggestions[j].Style.Number)
+	})
+
+	return suggestions
+}
+
+// overlapRatio returns the width of the overlap of two ranges relative to the width they span together.
+func (r Range) overlapRatio(other Range) float64 {
+	intersection := r.Intersection(other)
+	if !intersection.Applicable {
+		return 0
+	}
+
+	union := math.Max(r.Max, other.Max) - math.Min(r.Min, other.Min)
+	if union == 0 {
+		return 1
+	}
+
+	return (intersection.Max - intersection.Min) / union
+}
+
+// tagSimilarity returns the share of the tags of either style that both styles carry.
+func tagSimilarity(a, b []string) float64 {
+	inA := map[string]bool{}
+	all := map[string]bool{}
+
+	for _, tag := range a {
+		inA[tagKey(tag)] = true
+		all[tagKey(tag)] = true
+	}
+
+	shared := map[string]bool{}
+
+	for _, tag := range b {
+		if inA[tagKey(tag)] {
+			shared[tagKey(tag)] = true
+		}
+
+		all[tagKey(tag)] = true
+	}
+
+	if len(all) == 0 {
+		return 0
+	}
+
+	return float64(len(shared)) / float64(len(all))
+}
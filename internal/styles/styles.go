@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"sort"
 
 	"github.com/pkg/errors"
 )
@@ -13,7 +14,10 @@ import (
 var _ StyleRepo = (*StyleSource)(nil)
 
 type StyleSource struct {
-	styles map[string]Style
+	styles     map[string]Style
+	categories []Category
+	tags       map[string][]Style
+	tagNames   []string
 }
 
 type Style struct {
@@ -30,18 +34,14 @@ type Style struct {
 	History                   string `json:"history"`
 	CharacteristicIngredients string `json:"characteristicingredients"`
 	StyleComparison           string `json:"stylecomparison"`
-	IBUMin                    string `json:"ibumin"`
-	IBUMax                    string `json:"ibumax"`
-	OGMin                     string `json:"ogmin"`
-	OGMax                     string `json:"ogmax"`
-	FGMin                     string `json:"fgmin"`
-	FGMax                     string `json:"fgmax"`
-	ABVMin                    string `json:"abvmin"`
-	ABVMax                    string `json:"abvmax"`
-	SRMMin                    string `json:"srmmin"`
-	SRMMax                    string `json:"srmmax"`
 	CommercialExamples        string `json:"commercialexamples"`
+	EntryInstructions         string `json:"entryinstructions"`
 	Tags                      string `json:"tags"`
+	IBU                       Range  `json:"-"`
+	OG                        Range  `json:"-"`
+	FG                        Range  `json:"-"`
+	ABV                       Range  `json:"-"`
+	SRM                       Range  `json:"-"`
 }
 
 func NewStyleRepo(fileName string) (*StyleSource, error) {
@@ -56,31 +56,59 @@ func NewStyleRepo(fileName string) (*StyleSource, error) {
 		return nil, errors.Wrapf(err, "could read %s", fileName)
 	}
 
+	return parseStyles(data, fileName)
+}
+
+// parseStyles builds a StyleSource from the contents of a style guide file, named in errors by fileName.
+func parseStyles(data []byte, fileName string) (*StyleSource, error) {
 	s := StyleSource{
 		styles: make(map[string]Style),
 	}
 
-	styles := []Style{}
+	records := []styleRecord{}
 
-	err = json.Unmarshal(data, &styles)
+	err := json.Unmarshal(data, &records)
 	if err != nil {
 		return nil, errors.Wrap(err, "could unmarshal styles")
 	}
 
+	styles, err := validate(records)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not validate %s", fileName)
+	}
+
 	for _, style := range styles {
 		s.styles[style.Number] = style
 	}
 
+	s.index()
+
 	return &s, nil
 }
 
-func (s *StyleSource) Random(ctx context.Context) Style {
+// Random picks a style allowed by the filter. Picks are reproducible: the same seed and filter always pick the same
+// style. Nil is returned if no style is allowed by the filter.
+func (s *StyleSource) Random(ctx context.Context, filter RandomFilter, seed int64) *Style {
 	slice := make([]Style, 0, len(s.styles))
+
 	for _, style := range s.styles {
-		slice = append(slice, style)
+		if filter.Allows(style) {
+			slice = append(slice, style)
+		}
 	}
 
-	return slice[rand.Intn(len(slice))]
+	if len(slice) == 0 {
+		return nil
+	}
+
+	sort.Slice(slice, func(i, j int) bool {
+		return lessNumber(slice[i].Number, slice[j].Number)
+	})
+
+	//nolint: gosec
+	style := slice[rand.New(rand.NewSource(seed)).Intn(len(slice))]
+
+	return &style
 }
 
 func (s *StyleSource) Get(ctx context.Context, number string) *Style {
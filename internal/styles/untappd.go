@@ -0,0 +1,190 @@
+package styles
+
+import (
+	"context"
+	"strings"
+)
+
+const untappdFamilySeparator = " - "
+
+// untappdNumbers maps Untappd's style names, such as "IPA - New England / Hazy", to the numbers of the BJCP 2021
+// styles they correspond to. A family name on its own, such as "IPA", is the fallback for variants of the family
+// that are not listed. Untappd styles with no reasonable BJCP counterpart are left out.
+//
+//nolint:gochecknoglobals
+var untappdNumbers = untappdIndex(map[string]string{
+	"Altbier":                                    "7B",
+	"Barleywine":                                 "22C",
+	"Barleywine - American":                      "22C",
+	"Barleywine - English":                       "17D",
+	"Belgian Blonde":                             "25A",
+	"Belgian Dubbel":                             "26B",
+	"Belgian Enkel / Patersbier":                 "26A",
+	"Belgian Quadrupel":                          "26D",
+	"Belgian Strong Dark Ale":                    "26D",
+	"Belgian Strong Golden Ale":                  "25C",
+	"Belgian Tripel":                             "26C",
+	"Bière de Garde":                             "24C",
+	"Bitter":                                     "11B",
+	"Bitter - Best":                              "11B",
+	"Bitter - Extra Special / Strong (ESB)":      "11C",
+	"Bitter - Session / Ordinary":                "11A",
+	"Blonde Ale":                                 "18A",
+	"Bock":                                       "6C",
+	"Bock - Doppelbock":                          "9A",
+	"Bock - Eisbock (Traditional)":               "9B",
+	"Bock - Hell / Maibock / Lentebock":          "4C",
+	"Bock - Single / Traditional":                "6C",
+	"Bock - Weizenbock":                          "10C",
+	"Brett Beer":                                 "28A",
+	"Brown Ale":                                  "19C",
+	"Brown Ale - American":                       "19C",
+	"Brown Ale - English":                        "13B",
+	"California Common":                          "19B",
+	"Cream Ale":                                  "1C",
+	"Farmhouse Ale - Bière de Garde":             "24C",
+	"Farmhouse Ale - Sahti":                      "27I",
+	"Farmhouse Ale - Saison":                     "25B",
+	"Festbier":                                   "4B",
+	"Fruit Beer":                                 "29A",
+	"Golden Ale":                                 "12A",
+	"Grape Ale - Italian":                        "29D",
+	"Grape Ale - Other":                          "29D",
+	"Historical Beer - Grodziskie":               "27E",
+	"Historical Beer - Kentucky Common":          "27B",
+	"Historical Beer - Lichtenhainer":            "27C",
+	"IPA":                                        "21A",
+	"IPA - American":                             "21A",
+	"IPA - Belgian":                              "21B",
+	"IPA - Black / Cascadian Dark Ale":           "21B",
+	"IPA - Brut":                                 "21B",
+	"IPA - English":                              "12C",
+	"IPA - Imperial / Double":                    "22A",
+	"IPA - Imperial / Double New England / Hazy": "21C",
+	"IPA - New England / Hazy":                   "21C",
+	"IPA - Red":                                  "21B",
+	"IPA - Rye":                                  "21B",
+	"IPA - White / Wheat":                        "21B",
+	"Kölsch":                                     "5B",
+	"Lager":                                      "2A",
+	"Lager - Amber / Red":                        "2B",
+	"Lager - American":                           "1B",
+	"Lager - American Light":                     "1A",
+	"Lager - Dark":                               "2C",
+	"Lager - Dortmunder / Export":                "5C",
+	"Lager - Helles":                             "4A",
+	"Lager - Japanese Rice":                      "2A",
+	"Lager - Kellerbier / Zwickelbier":           "27A",
+	"Lager - Leichtbier":                         "5A",
+	"Lager - Mexican":                            "2A",
+	"Lager - Munich Dunkel":                      "8A",
+	"Lager - Märzen":                             "6A",
+	"Lager - Pale":                               "2A",
+	"Lager - Vienna":                             "7A",
+	"Lager - Winter":                             "30C",
+	"Lambic - Framboise":                         "23F",
+	"Lambic - Fruit":                             "23F",
+	"Lambic - Gueuze":                            "23E",
+	"Lambic - Kriek":                             "23F",
+	"Lambic - Traditional":                       "23D",
+	"Mild - Dark":                                "13A",
+	"Old Ale":                                    "17B",
+	"Pale Ale":                                   "18B",
+	"Pale Ale - American":                        "18B",
+	"Pale Ale - Australian":                      "12B",
+	"Pale Ale - Belgian":                         "24B",
+	"Pale Ale - English":                         "12A",
+	"Pale Ale - New England / Hazy":              "21C",
+	"Pilsner":                                    "5D",
+	"Pilsner - Czech / Bohemian":                 "3B",
+	"Pilsner - German":                           "5D",
+	"Pilsner - Other":                            "2A",
+	"Porter":                                     "20A",
+	"Porter - American":                          "20A",
+	"Porter - Baltic":                            "9C",
+	"Porter - English":                           "13C",
+	"Pumpkin / Yam Beer":                         "30B",
+	"Rauchbier":                                  "6B",
+	"Red Ale - American Amber / Red":             "19A",
+	"Red Ale - Imperial / Double":                "22B",
+	"Red Ale - Irish":                            "15A",
+	"Roggenbier":                                 "27H",
+	"Schwarzbier":                                "8B",
+	"Scotch Ale / Wee Heavy":                     "17C",
+	"Scottish Ale":                               "14B",
+	"Scottish Export Ale":                        "14C",
+	"Smoked Beer":                                "32B",
+	"Sour - Berliner Weisse":                     "23A",
+	"Sour - Flanders Oud Bruin":                  "23C",
+	"Sour - Flanders Red Ale":                    "23B",
+	"Sour - Fruited":                             "28C",
+	"Sour - Traditional Gose":                    "23G",
+	"Spiced / Herbed Beer":                       "30A",
+	"Stout":                                      "20B",
+	"Stout - American":                           "20B",
+	"Stout - Foreign / Export":                   "16D",
+	"Stout - Imperial / Double":                  "20C",
+	"Stout - Irish Dry":                          "15B",
+	"Stout - Milk / Sweet":                       "16A",
+	"Stout - Oatmeal":                            "16B",
+	"Stout - Russian Imperial":                   "20C",
+	"Strong Ale - American":                      "22B",
+	"Strong Ale - English":                       "17A",
+	"Wheat Beer - American Pale Wheat":           "1D",
+	"Wheat Beer - Dunkelweizen":                  "10B",
+	"Wheat Beer - Hefeweizen":                    "10A",
+	"Wheat Beer - Kristallweizen":                "10A",
+	"Wheat Beer - Wheat Wine":                    "22D",
+	"Wheat Beer - Witbier / Blanche":             "24A",
+	"Wild Ale - American":                        "28B",
+	"Wild Ale - Other":                           "28B",
+	"Winter Ale":                                 "30C",
+	"Winter Warmer":                              "30C",
+})
+
+// FromUntappd resolves an Untappd style name to a style in the guide. Styles in the mapping table are resolved by
+// number, which assumes the guide numbers its styles like the BJCP 2021 guide, and other styles are resolved by name,
+// either as written or with the family moved to the end, so "Lager - Vienna" resolves to "Vienna Lager". Unlisted
+// variants fall back to the family, so an unknown kind of IPA is still an IPA. It returns nil when nothing matches.
+func (s *StyleSource) FromUntappd(ctx context.Context, untappdStyle string) *Style {
+	family, variant, hasVariant := strings.Cut(untappdStyle, untappdFamilySeparator)
+
+	candidates := []string{untappdStyle}
+	if hasVariant {
+		candidates = append(candidates, variant+" "+family)
+	}
+
+	for _, candidate := range candidates {
+		if style := s.Get(ctx, untappdNumbers[untappdKey(candidate)]); style != nil {
+			return style
+		}
+
+		for _, style := range s.styles {
+			if untappdKey(style.Name) == untappdKey(candidate) {
+				return &style
+			}
+		}
+	}
+
+	if hasVariant {
+		return s.Get(ctx, untappdNumbers[untappdKey(family)])
+	}
+
+	return nil
+}
+
+// untappdKey reduces a style name to lowercase words separated by single spaces, so that punctuation, accents and
+// slashes do not get in the way of matching.
+func untappdKey(name string) string {
+	return strings.Join(strings.FieldsFunc(normalize(name), isSeparator), " ")
+}
+
+func untappdIndex(numbers map[string]string) map[string]string {
+	index := make(map[string]string, len(numbers))
+
+	for name, number := range numbers {
+		index[untappdKey(name)] = number
+	}
+
+	return index
+}
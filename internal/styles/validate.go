@@ -0,0 +1,121 @@
+package styles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// styleRecord is a style as it is stored in a style guide file, where the vitals are strings that are empty when
+// they are not applicable.
+type styleRecord struct {
+	Style
+	IBUMin string `json:"ibumin"`
+	IBUMax string `json:"ibumax"`
+	OGMin  string `json:"ogmin"`
+	OGMax  string `json:"ogmax"`
+	FGMin  string `json:"fgmin"`
+	FGMax  string `json:"fgmax"`
+	ABVMin string `json:"abvmin"`
+	ABVMax string `json:"abvmax"`
+	SRMMin string `json:"srmmin"`
+	SRMMax string `json:"srmmax"`
+}
+
+// ValidationError reports every problem found in a style guide.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("style guide has %d problems:\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+// validate converts the records into styles, checking for missing names and numbers, duplicate numbers and malformed
+// vitals. All problems are collected into a single ValidationError rather than stopping at the first.
+func validate(records []styleRecord) ([]Style, error) {
+	var (
+		styles   = make([]Style, 0, len(records))
+		problems []string
+		seen     = map[string]int{}
+	)
+
+	for i, record := range records {
+		style := record.Style
+		entry := fmt.Sprintf("entry %d (%s)", i, style.Number)
+
+		if style.Number == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing number", entry))
+		} else if first, ok := seen[style.Number]; ok {
+			problems = append(problems, fmt.Sprintf("%s: duplicate number, first used by entry %d", entry, first))
+		} else {
+			seen[style.Number] = i
+		}
+
+		if strings.TrimSpace(style.Name) == "" {
+			problems = append(problems, fmt.Sprintf("%s: missing name", entry))
+		}
+
+		vitals := []struct {
+			name     string
+			min, max string
+			target   *Range
+		}{
+			{name: "IBU", min: record.IBUMin, max: record.IBUMax, target: &style.IBU},
+			{name: "OG", min: record.OGMin, max: record.OGMax, target: &style.OG},
+			{name: "FG", min: record.FGMin, max: record.FGMax, target: &style.FG},
+			{name: "ABV", min: record.ABVMin, max: record.ABVMax, target: &style.ABV},
+			{name: "SRM", min: record.SRMMin, max: record.SRMMax, target: &style.SRM},
+		}
+
+		for _, vital := range vitals {
+			r, problem := parseRange(vital.min, vital.max)
+			if problem != "" {
+				problems = append(problems, fmt.Sprintf("%s: %s %s", entry, vital.name, problem))
+
+				continue
+			}
+
+			*vital.target = r
+		}
+
+		styles = append(styles, style)
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return styles, nil
+}
+
+// parseRange parses the bounds of a vital. Both bounds must be given, or neither when it is not applicable. A
+// description of the problem is returned if the bounds are invalid.
+func parseRange(minValue, maxValue string) (Range, string) {
+	minValue, maxValue = strings.TrimSpace(minValue), strings.TrimSpace(maxValue)
+
+	switch {
+	case minValue == "" && maxValue == "":
+		return Range{}, ""
+	case minValue == "":
+		return Range{}, "has a maximum but no minimum"
+	case maxValue == "":
+		return Range{}, "has a minimum but no maximum"
+	}
+
+	lo, err := strconv.ParseFloat(minValue, 64)
+	if err != nil {
+		return Range{}, fmt.Sprintf("has an invalid minimum %q", minValue)
+	}
+
+	hi, err := strconv.ParseFloat(maxValue, 64)
+	if err != nil {
+		return Range{}, fmt.Sprintf("has an invalid maximum %q", maxValue)
+	}
+
+	if lo > hi {
+		return Range{}, fmt.Sprintf("minimum %s is greater than maximum %s", minValue, maxValue)
+	}
+
+	return Range{Min: lo, Max: hi, Applicable: true}, ""
+}
@@ -0,0 +1,60 @@
+package styles
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// Range is an inclusive range of a vital statistic. Specialty styles leave some vitals unspecified because they
+// depend on the base style, in which case the Range is not applicable and Min and Max are meaningless.
+type Range struct {
+	Min        float64
+	Max        float64
+	Applicable bool
+}
+
+// VitalsFilter constrains the vitals of the styles returned by Find. A nil Range leaves that vital unconstrained.
+type VitalsFilter struct {
+	IBU *Range
+	OG  *Range
+	FG  *Range
+	ABV *Range
+	SRM *Range
+}
+
+// Overlaps reports whether any value falls within both ranges.
+func (r Range) Overlaps(other Range) bool {
+	return r.Applicable && other.Applicable && r.Max >= other.Min && r.Min <= other.Max
+}
+
+// Intersection returns the values that fall within both ranges, which is not applicable when they do not overlap.
+func (r Range) Intersection(other Range) Range {
+	if !r.Overlaps(other) {
+		return Range{}
+	}
+
+	return Range{Min: math.Max(r.Min, other.Min), Max: math.Min(r.Max, other.Max), Applicable: true}
+}
+
+// Find returns every style whose vitals overlap all of the ranges in the filter, ordered by style number.
+func (s *StyleSource) Find(ctx context.Context, filter VitalsFilter) []Style {
+	results := []Style{}
+
+	for _, style := range s.styles {
+		if matches(style.IBU, filter.IBU) && matches(style.OG, filter.OG) && matches(style.FG, filter.FG) &&
+			matches(style.ABV, filter.ABV) && matches(style.SRM, filter.SRM) {
+			results = append(results, style)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return lessNumber(results[i].Number, results[j].Number)
+	})
+
+	return results
+}
+
+func matches(r Range, filter *Range) bool {
+	return filter == nil || r.Overlaps(*filter)
+}
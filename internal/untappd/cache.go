@@ -0,0 +1,150 @@
+package untappd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// ScrapeFunc scrapes the venue at path.
+type ScrapeFunc func(path string) (Result, error)
+
+// Cache keeps the result of scraping each venue for a while so that repeated requests for the same venue do not
+// each scrape Untappd. Concurrent requests for a venue that is not cached share a single scrape. When persistPath is
+// set, Save writes the cache there and it is read back when the cache is created, so it survives restarts.
+type Cache struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	scrape      ScrapeFunc
+	persistPath string
+	results     map[string]Result
+	calls       map[string]*call
+	dirty       bool
+}
+
+type call struct {
+	done   chan struct{}
+	result Result
+	err    error
+}
+
+// NewCache creates a cache that keeps results for ttl, loading any results persisted at persistPath.
+func NewCache(ttl time.Duration, scrape ScrapeFunc, persistPath string) (*Cache, error) {
+	c := &Cache{
+		ttl:         ttl,
+		scrape:      scrape,
+		persistPath: persistPath,
+		results:     make(map[string]Result),
+		calls:       make(map[string]*call),
+	}
+
+	if persistPath == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(persistPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	} else if err != nil {
+		return nil, errors.WrapPrefix(err, "could not read scrape cache", 0)
+	}
+
+	if err := json.Unmarshal(data, &c.results); err != nil {
+		return nil, errors.WrapPrefix(err, "could not unmarshal scrape cache", 0)
+	}
+
+	return c, nil
+}
+
+// Get returns the result of scraping the venue at path, scraping it only when it is not cached, the cached result
+// is older than the cache's TTL or refresh is set. A request that arrives while the venue is being scraped waits for
+// that scrape instead of starting another.
+func (c *Cache) Get(path string, refresh bool) (Result, error) {
+	c.mu.Lock()
+
+	if result, ok := c.results[path]; ok && !refresh && time.Since(result.ScrapedAt) < c.ttl {
+		c.mu.Unlock()
+
+		return result, nil
+	}
+
+	if inFlight, ok := c.calls[path]; ok {
+		c.mu.Unlock()
+		<-inFlight.done
+
+		return inFlight.result, inFlight.err
+	}
+
+	current := &call{done: make(chan struct{})}
+	c.calls[path] = current
+	c.mu.Unlock()
+
+	result, err := c.scrape(path)
+	result.ScrapedAt = time.Now()
+	current.result = result
+	current.err = err
+
+	c.mu.Lock()
+	delete(c.calls, path)
+
+	if err == nil {
+		c.results[path] = current.result
+		c.dirty = true
+	}
+
+	c.mu.Unlock()
+	close(current.done)
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	return current.result, nil
+}
+
+// Save writes the cached results to persistPath, if it is set and anything has been scraped since the last save. It
+// is meant to be run by the scheduler. The results are written to a temporary file that then replaces the cache, so
+// that a crash midway does not leave a truncated cache behind.
+func (c *Cache) Save(ctx context.Context, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.persistPath == "" || !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.results)
+	if err != nil {
+		return errors.WrapPrefix(err, "could not marshal scrape cache", 0)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.persistPath), filepath.Base(c.persistPath)+".*")
+	if err != nil {
+		return errors.WrapPrefix(err, "could not create scrape cache file", 0)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return errors.WrapPrefix(err, "could not write scrape cache", 0)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.WrapPrefix(err, "could not close scrape cache file", 0)
+	}
+
+	if err := os.Rename(tmp.Name(), c.persistPath); err != nil {
+		return errors.WrapPrefix(err, "could not replace scrape cache", 0)
+	}
+
+	c.dirty = false
+
+	return nil
+}
@@ -1,16 +1,32 @@
 package untappd
 
 import (
+	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/gocolly/colly/v2"
 )
 
-const base = "https://untappd.com/v/"
+const DefaultBaseURL = "https://untappd.com/v/"
+
+// Scraper scrapes venue pages. Fields that are not set fall back to Untappd's URL and colly's defaults, so the zero
+// value scrapes Untappd.
+type Scraper struct {
+	// BaseURL is joined with a venue path to form the URL of the venue's page, DefaultBaseURL when empty.
+	BaseURL string
+	// Client makes the requests. It is copied, so setting Timeout does not change it.
+	Client *http.Client
+	// UserAgent is sent with every request when set.
+	UserAgent string
+	// Timeout bounds each request when set.
+	Timeout time.Duration
+}
 
 type Patron struct {
 	Name     string
@@ -31,13 +47,44 @@ type MenuItem struct {
 	IBU     string
 }
 
-func Scrape(path string) ([]Menu, []Patron, error) {
-	c := colly.NewCollector()
+// Result is what was scraped from a venue's page.
+type Result struct {
+	Menus   []Menu
+	Patrons []Patron
+	// Warnings describe the parts of the page that could not be parsed and were skipped.
+	Warnings []Warning
+	// NoMenus is set when the page had no menu sections at all, which most likely means that Untappd has changed
+	// the layout of its venue pages rather than that the venue has nothing on.
+	NoMenus   bool
+	ScrapedAt time.Time
+}
+
+// Warning describes a part of a venue page that was skipped because it could not be parsed.
+type Warning struct {
+	// Section is the part of the page, such as a menu's name or "patrons".
+	Section string
+	// Text is the text that could not be parsed.
+	Text   string
+	Reason string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s (%q)", w.Section, w.Reason, w.Text)
+}
+
+// Scrape scrapes the menus and loyal patrons of the venue at path, which is the part of the venue's URL after the
+// base URL. Menu items and patrons that cannot be parsed are skipped and reported as warnings rather than failing
+// the whole scrape.
+func (s *Scraper) Scrape(path string) (Result, error) {
+	c := s.collector()
 
 	var rankCtr int
 
-	menus := []Menu{}
-	patrons := []Patron{}
+	result := Result{
+		Menus:    []Menu{},
+		Patrons:  []Patron{},
+		Warnings: []Warning{},
+	}
 
 	c.OnHTML(`.menu-section`, func(e *colly.HTMLElement) {
 		menuName := e.DOM.Find("div.menu-section-header h4").Clone()
@@ -49,29 +96,43 @@ func Scrape(path string) ([]Menu, []Patron, error) {
 		}
 
 		e.ForEach("li.menu-item", func(_ int, el *colly.HTMLElement) {
-			item := MenuItem{}
-			item.Name = normalizeWhitespace(strings.TrimSpace(el.DOM.Find("h5 a").Text()))
-			item.Brewery = strings.TrimSpace(el.DOM.Find("h6 span a").First().Text())
-			item.Style = strings.TrimSpace(el.DOM.Find("h5 em").Text())
-			abvAndIbu := strings.TrimSpace(el.DOM.Find("h6 span").Text())
-			parts := strings.Split(abvAndIbu, "•")
-			item.ABV = strings.TrimSpace(strings.Split(parts[0], " ")[0])
-			item.IBU = "N/A"
-
-			if len(parts) > 1 {
-				ibuParts := strings.Split(parts[1], "IBU")
-				if len(ibuParts) > 0 {
-					item.IBU = strings.TrimSpace(ibuParts[0])
+			item := MenuItem{
+				Name:    normalizeWhitespace(el.DOM.Find("h5 a").Text()),
+				Brewery: strings.TrimSpace(el.DOM.Find("h6 span a").First().Text()),
+				Style:   strings.TrimSpace(el.DOM.Find("h5 em").Text()),
+				ABV:     "N/A",
+				IBU:     "N/A",
+			}
+
+			if item.Name == "" {
+				result.Warnings = append(result.Warnings, Warning{
+					Section: menu.Name,
+					Text:    normalizeWhitespace(el.Text),
+					Reason:  "menu item has no name",
+				})
+
+				return
+			}
+
+			// The details read like "6.5% ABV • 40 IBU • Brewery", with the IBU left out for some beers.
+			for _, part := range strings.Split(el.DOM.Find("h6 span").Text(), "•") {
+				fields := strings.Fields(part)
+
+				switch {
+				case len(fields) == 2 && fields[1] == "ABV":
+					item.ABV = fields[0]
+				case len(fields) == 2 && fields[1] == "IBU":
+					item.IBU = fields[0]
 				}
 			}
 
 			menu.Items = append(menu.Items, item)
 		})
 
-		menus = append(menus, menu)
+		result.Menus = append(result.Menus, menu)
 	})
 
-	patronRegEx := regexp.MustCompile(`^(.*) \((\d+) check-ins\)$`)
+	patronRegEx := regexp.MustCompile(`^(.*) \(([\d,]+) check-ins?\)$`)
 
 	c.OnHTML(`a[data-href=":loyal/drinkers"]`, func(e *colly.HTMLElement) {
 		title := e.Attr("original-title")
@@ -85,35 +146,67 @@ func Scrape(path string) ([]Menu, []Patron, error) {
 
 		rankCtr++
 
-		p := Patron{
-			Rank: rankCtr,
-		}
-
 		matches := patronRegEx.FindStringSubmatch(title)
-		if len(matches) == 3 {
-			p.Name = matches[1]
+		if len(matches) != 3 { //nolint: gomnd
+			result.Warnings = append(result.Warnings, Warning{
+				Section: "patrons",
+				Text:    title,
+				Reason:  "patron is not in the form name (n check-ins)",
+			})
 
-			checkIns, err := strconv.Atoi(matches[2])
-			if err != nil {
-				panic(err)
-			}
+			return
+		}
 
-			p.CheckIns = checkIns
+		checkIns, err := strconv.Atoi(strings.ReplaceAll(matches[2], ",", ""))
+		if err != nil {
+			result.Warnings = append(result.Warnings, Warning{
+				Section: "patrons",
+				Text:    title,
+				Reason:  "check-in count is not a number",
+			})
 
-			patrons = append(patrons, p)
+			return
 		}
+
+		result.Patrons = append(result.Patrons, Patron{Name: matches[1], CheckIns: checkIns, Rank: rankCtr})
 	})
 
-	u, err := url.JoinPath(base, path)
+	baseURL := s.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	u, err := url.JoinPath(baseURL, path)
 	if err != nil {
-		return nil, nil, errors.WrapPrefix(err, "could not join path", 0)
+		return Result{}, errors.WrapPrefix(err, "could not join path", 0)
 	}
 
 	if err := c.Visit(u); err != nil {
-		return nil, nil, errors.WrapPrefix(err, "could not visit url", 0)
+		return Result{}, errors.WrapPrefix(err, "could not visit url", 0)
+	}
+
+	result.NoMenus = len(result.Menus) == 0
+
+	return result, nil
+}
+
+func (s *Scraper) collector() *colly.Collector {
+	c := colly.NewCollector()
+
+	if s.Client != nil {
+		client := *s.Client
+		c.SetClient(&client)
+	}
+
+	if s.UserAgent != "" {
+		c.UserAgent = s.UserAgent
+	}
+
+	if s.Timeout > 0 {
+		c.SetRequestTimeout(s.Timeout)
 	}
 
-	return menus, patrons, nil
+	return c
 }
 
 func normalizeWhitespace(input string) string {
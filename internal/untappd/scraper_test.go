@@ -0,0 +1,175 @@
+package untappd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func scrapeFixture(t *testing.T, path string) (Result, error) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(srv.Close)
+
+	scraper := Scraper{BaseURL: srv.URL}
+
+	return scraper.Scrape(path)
+}
+
+func TestScrapeMenusAndPatrons(t *testing.T) {
+	result, err := scrapeFixture(t, "venue.html")
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	wantMenus := []Menu{
+		{
+			Name: "Draft",
+			Items: []MenuItem{
+				{
+					Name:    "1. Hazy Days",
+					Brewery: "Brown Beard Brewing Company",
+					Style:   "IPA - New England / Hazy",
+					ABV:     "6.5%",
+					IBU:     "40",
+				},
+				{
+					Name:    "2. Helles",
+					Brewery: "Brown Beard Brewing Company",
+					Style:   "Lager - Helles",
+					ABV:     "4.8%",
+					IBU:     "N/A",
+				},
+				{
+					Name:    "3. Mystery Cask",
+					Brewery: "Brown Beard Brewing Company",
+					Style:   "Bitter - Best",
+					ABV:     "N/A",
+					IBU:     "N/A",
+				},
+			},
+		},
+		{
+			Name: "Cans",
+			Items: []MenuItem{
+				{
+					Name:    "1. Night Shift",
+					Brewery: "Other Brewing",
+					Style:   "Stout - Imperial / Double",
+					ABV:     "11.2%",
+					IBU:     "65",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(result.Menus, wantMenus) {
+		t.Errorf("Menus = %+v, want %+v", result.Menus, wantMenus)
+	}
+
+	wantPatrons := []Patron{
+		{Name: "First Patron", CheckIns: 1234, Rank: 1},
+		{Name: "Second Patron", CheckIns: 1, Rank: 2},
+	}
+
+	if !reflect.DeepEqual(result.Patrons, wantPatrons) {
+		t.Errorf("Patrons = %+v, want %+v", result.Patrons, wantPatrons)
+	}
+
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", result.Warnings)
+	}
+
+	if result.NoMenus {
+		t.Error("NoMenus = true, want false")
+	}
+}
+
+func TestScrapeMalformedEntries(t *testing.T) {
+	result, err := scrapeFixture(t, "malformed.html")
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	wantMenus := []Menu{
+		{
+			Name: "Draft",
+			Items: []MenuItem{
+				{Name: "2. Good Pils", Brewery: "Good Brewing", Style: "Pilsner - German", ABV: "5.0%", IBU: "35"},
+				{
+					Name:    "3. Garbled Details",
+					Brewery: "Good Brewing",
+					Style:   "Pale Ale - American",
+					ABV:     "N/A",
+					IBU:     "N/A",
+				},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(result.Menus, wantMenus) {
+		t.Errorf("Menus = %+v, want %+v", result.Menus, wantMenus)
+	}
+
+	// Patrons without a title are ignored rather than ranked, while malformed ones still take up a rank.
+	wantPatrons := []Patron{{Name: "Fine Patron", CheckIns: 12, Rank: 3}}
+
+	if !reflect.DeepEqual(result.Patrons, wantPatrons) {
+		t.Errorf("Patrons = %+v, want %+v", result.Patrons, wantPatrons)
+	}
+
+	wantWarnings := []Warning{
+		{
+			Section: "Draft",
+			Text:    "IPA - American 7.0% ABV • 60 IBU • Unknown Brewing",
+			Reason:  "menu item has no name",
+		},
+		{
+			Section: "patrons",
+			Text:    "Somebody without a count",
+			Reason:  "patron is not in the form name (n check-ins)",
+		},
+		{
+			Section: "patrons",
+			Text:    "Huge Patron (99999999999999999999 check-ins)",
+			Reason:  "check-in count is not a number",
+		},
+	}
+
+	if !reflect.DeepEqual(result.Warnings, wantWarnings) {
+		t.Errorf("Warnings = %+v, want %+v", result.Warnings, wantWarnings)
+	}
+
+	if result.NoMenus {
+		t.Error("NoMenus = true, want false")
+	}
+}
+
+func TestScrapeNoMenus(t *testing.T) {
+	result, err := scrapeFixture(t, "nomenus.html")
+	if err != nil {
+		t.Fatalf("Scrape() error = %v", err)
+	}
+
+	if !result.NoMenus {
+		t.Error("NoMenus = false, want true")
+	}
+
+	if len(result.Menus) != 0 {
+		t.Errorf("Menus = %+v, want none", result.Menus)
+	}
+
+	wantPatrons := []Patron{{Name: "Only Patron", CheckIns: 3, Rank: 1}}
+
+	if !reflect.DeepEqual(result.Patrons, wantPatrons) {
+		t.Errorf("Patrons = %+v, want %+v", result.Patrons, wantPatrons)
+	}
+}
+
+func TestScrapeNotFound(t *testing.T) {
+	if _, err := scrapeFixture(t, "missing.html"); err == nil {
+		t.Error("Scrape() error = nil, want an error for a missing page")
+	}
+}
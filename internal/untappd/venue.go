@@ -0,0 +1,36 @@
+package untappd
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+var venuePathRegEx = regexp.MustCompile(`^/v/([^/]+/\d+)/?$`) //nolint:gochecknoglobals
+
+// VenuePath extracts the path that Scraper.Scrape expects from a venue URL such as
+// https://untappd.com/v/brown-beard-brewing-company/11844307. The scheme may be left out.
+func VenuePath(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.WrapPrefix(err, "could not parse url", 0)
+	}
+
+	if host := strings.TrimPrefix(strings.ToLower(u.Host), "www."); host != "untappd.com" {
+		return "", errors.Errorf("%s is not an Untappd url", rawURL)
+	}
+
+	matches := venuePathRegEx.FindStringSubmatch(u.Path)
+	if len(matches) != 2 { //nolint: gomnd
+		return "", errors.Errorf("%s is not an Untappd venue url", rawURL)
+	}
+
+	return matches[1], nil
+}